@@ -10,16 +10,20 @@ import (
 	"github.com/TFX0019/api-go-gds/features/banners"
 	"github.com/TFX0019/api-go-gds/features/coupons"
 	"github.com/TFX0019/api-go-gds/features/customers"
-	"github.com/TFX0019/api-go-gds/features/dashboard"
 	"github.com/TFX0019/api-go-gds/features/daily_credits"
+	"github.com/TFX0019/api-go-gds/features/dashboard"
+	"github.com/TFX0019/api-go-gds/features/exchange_rates"
 	"github.com/TFX0019/api-go-gds/features/helps"
 	"github.com/TFX0019/api-go-gds/features/links"
 	"github.com/TFX0019/api-go-gds/features/materials"
 	"github.com/TFX0019/api-go-gds/features/plans"
+	"github.com/TFX0019/api-go-gds/features/product_templates"
 	"github.com/TFX0019/api-go-gds/features/products"
+	"github.com/TFX0019/api-go-gds/features/size_charts"
 	"github.com/TFX0019/api-go-gds/features/subscriptions"
 	"github.com/TFX0019/api-go-gds/features/support"
 	"github.com/TFX0019/api-go-gds/features/tasks"
+	"github.com/TFX0019/api-go-gds/features/tax_profiles"
 	"github.com/TFX0019/api-go-gds/features/user"
 	"github.com/TFX0019/api-go-gds/features/wallets"
 	"github.com/TFX0019/api-go-gds/pkg/config"
@@ -39,12 +43,79 @@ func main() {
 	database.ConnectDB()
 
 	// 3. Migrations
+	// Extensions used by the accent-insensitive fuzzy customer search
+	for _, extension := range []string{"unaccent", "pg_trgm"} {
+		if err := database.DB.Exec("CREATE EXTENSION IF NOT EXISTS " + extension).Error; err != nil {
+			log.Printf("Failed to enable extension %s: %v", extension, err)
+		}
+	}
+
 	// Migrate Auth models
 	// Migrate models
-	if err := database.DB.AutoMigrate(&auth.User{}, &auth.VerificationCode{}, &auth.Role{}, &auth.Session{}, &customers.Customer{}, &products.Product{}, &products.ProductImage{}, &materials.Material{}, &tasks.Task{}, &wallets.Wallet{}, &wallets.CreditTransaction{}, &subscriptions.Subscription{}, &subscriptions.Transaction{}, &plans.Plan{}, &support.SupportCategory{}, &support.Support{}, &ai.AIGeneration{}, &ai.AISuggestion{}, &links.Link{}, &banners.Banner{}, &daily_credits.DailyCredit{}, &coupons.Coupon{}, &helps.Help{}); err != nil {
+	if err := database.DB.AutoMigrate(&auth.User{}, &auth.VerificationCode{}, &auth.Role{}, &auth.Session{}, &customers.Customer{}, &customers.MeasurementSnapshot{}, &customers.MeasurementField{}, &customers.CustomerMeasurementValue{}, &customers.CustomerNote{}, &products.Product{}, &products.ProductImage{}, &products.ProductShareLink{}, &products.ProductMaterial{}, &products.TimeEntry{}, &products.ProductStatusHistory{}, &products.Payment{}, &products.Document{}, &products.DocumentSequence{}, &products.QuoteRevision{}, &products.ProductFitting{}, &products.ProductAdjustment{}, &product_templates.ProductTemplate{}, &product_templates.TemplateMaterial{}, &product_templates.TemplateImage{}, &materials.Material{}, &materials.StockMovement{}, &tasks.Task{}, &wallets.Wallet{}, &wallets.CreditTransaction{}, &subscriptions.Subscription{}, &subscriptions.Transaction{}, &plans.Plan{}, &support.SupportCategory{}, &support.Support{}, &ai.AIGeneration{}, &ai.AISuggestion{}, &links.Link{}, &banners.Banner{}, &daily_credits.DailyCredit{}, &coupons.Coupon{}, &helps.Help{}, &exchange_rates.ExchangeRate{}, &tax_profiles.TaxProfile{}, &size_charts.SizeChart{}, &size_charts.SizeChartSize{}); err != nil {
 		log.Fatal("Migration failed: ", err)
 	}
 
+	// AutoMigrate does not alter existing constraints: orders keep the
+	// measurements they were made with, so deleting a customer must not
+	// drop the snapshots they pin
+	if err := database.DB.Exec(`DO $$ BEGIN
+		IF EXISTS (SELECT 1 FROM information_schema.referential_constraints
+			WHERE constraint_name = 'fk_products_measurement_snapshot' AND delete_rule <> 'RESTRICT') THEN
+			ALTER TABLE products DROP CONSTRAINT fk_products_measurement_snapshot,
+				ADD CONSTRAINT fk_products_measurement_snapshot FOREIGN KEY (measurement_snapshot_id)
+				REFERENCES customer_measurements(id) ON DELETE RESTRICT;
+		END IF;
+	END $$`).Error; err != nil {
+		log.Printf("Failed to update the measurement snapshot constraint: %v", err)
+	}
+
+	// Orders paid before payments were tracked get a single payment for their
+	// total so the ledger and the P&L keep counting them
+	if err := database.DB.Exec(`INSERT INTO product_payments (product_id, user_id, amount, method, paid_at, note)
+		SELECT p.id, p.user_id, p.total, 'other', p.date_paid, 'Recorded before payments were tracked'
+		FROM products p
+		WHERE p.date_paid IS NOT NULL AND p.total > 0
+		AND NOT EXISTS (SELECT 1 FROM product_payments pp WHERE pp.product_id = p.id)`).Error; err != nil {
+		log.Printf("Failed to backfill payments: %v", err)
+	}
+
+	// Customers measured before measurement history was kept get their
+	// current measurements as a first snapshot, so orders can pin them
+	if err := database.DB.Exec(`INSERT INTO customer_measurements (customer_id, user_id,
+			back, neck, front_size, armhole, back_size, bust_chest, waist, hip,
+			rise_height, skirt_length, pants_length, knee_width, hem_width, sleeve_length, cuff_size,
+			custom_values, created_at)
+		SELECT c.id, c.user_id,
+			c.back, c.neck, c.front_size, c.armhole, c.back_size, c.bust_chest, c.waist, c.hip,
+			c.rise_height, c.skirt_length, c.pants_length, c.knee_width, c.hem_width, c.sleeve_length, c.cuff_size,
+			(SELECT json_object_agg(v.field_id::text, v.value)::text FROM customer_measurement_values v WHERE v.customer_id = c.id),
+			c.updated_at
+		FROM customers c
+		WHERE (COALESCE(c.back, c.neck, c.front_size, c.armhole, c.back_size, c.bust_chest, c.waist, c.hip,
+				c.rise_height, c.skirt_length, c.pants_length, c.knee_width, c.hem_width, c.sleeve_length, c.cuff_size) IS NOT NULL
+			OR EXISTS (SELECT 1 FROM customer_measurement_values v WHERE v.customer_id = c.id))
+		AND NOT EXISTS (SELECT 1 FROM customer_measurements cm WHERE cm.customer_id = c.id)`).Error; err != nil {
+		log.Printf("Failed to backfill measurement history: %v", err)
+	}
+
+	// Orders priced before the hand-entered labor cost was kept apart start
+	// from their current one when no time is logged
+	if err := database.DB.Exec(`UPDATE products SET manual_hours_cost = hours_cost
+		WHERE logged_hours = 0 AND manual_hours_cost <> hours_cost`).Error; err != nil {
+		log.Printf("Failed to backfill manual labor costs: %v", err)
+	}
+
+	// Materials stocked before movements were tracked get their quantity as
+	// an opening balance so the ledger adds up to it
+	if err := database.DB.Exec(`INSERT INTO material_stock_movements (material_id, user_id, type, quantity, note, occurred_at)
+		SELECT m.id, m.user_id, 'adjustment', m.quantity, 'Opening balance', m.created_at
+		FROM materials m
+		WHERE m.quantity <> 0
+		AND NOT EXISTS (SELECT 1 FROM material_stock_movements sm WHERE sm.material_id = m.id)`).Error; err != nil {
+		log.Printf("Failed to backfill stock movements: %v", err)
+	}
+
 	// Seed Roles
 	var roles = []string{"admin", "member"}
 	for _, roleName := range roles {
@@ -61,7 +132,6 @@ func main() {
 			ProductID:    "free_tier",
 			Title:        "Free Tier",
 			Description:  "Starter plan for new users",
-			Price:        0,
 			Benefits:     []string{"20 Customer Limit", "20 Product Limit", "20 Material Limit", "20 Task Limit"},
 			MaxCustomers: 20,
 			MaxProducts:  20,
@@ -102,15 +172,34 @@ func main() {
 	customersController := customers.NewController(customersService)
 	customers.RegisterRoutes(app, customersController)
 
+	// Exchange Rates Feature
+	exchangeRatesRepo := exchange_rates.NewRepository(database.DB)
+	exchangeRatesService := exchange_rates.NewService(exchangeRatesRepo)
+	exchangeRatesController := exchange_rates.NewController(exchangeRatesService)
+	exchange_rates.RegisterRoutes(app, exchangeRatesController)
+
+	// Tax Profiles Feature
+	taxProfilesRepo := tax_profiles.NewRepository(database.DB)
+	taxProfilesService := tax_profiles.NewService(taxProfilesRepo)
+	taxProfilesController := tax_profiles.NewController(taxProfilesService)
+	tax_profiles.RegisterRoutes(app, taxProfilesController)
+
 	// Products Feature
+	materialsRepo := materials.NewRepository(database.DB)
 	productsRepo := products.NewRepository(database.DB)
-	productsService := products.NewService(productsRepo, authRepo, plansRepo)
+	productsService := products.NewService(productsRepo, authRepo, plansRepo, customersRepo, materialsRepo, exchangeRatesRepo, taxProfilesRepo)
 	productsController := products.NewController(productsService)
 	products.RegisterRoutes(app, productsController)
+	products.RegisterPublicRoutes(app, productsController)
+
+	// Product Templates Feature
+	productTemplatesRepo := product_templates.NewRepository(database.DB)
+	productTemplatesService := product_templates.NewService(productTemplatesRepo, materialsRepo, productsService)
+	productTemplatesController := product_templates.NewController(productTemplatesService)
+	product_templates.RegisterRoutes(app, productTemplatesController)
 
 	// Materials Feature
-	materialsRepo := materials.NewRepository(database.DB)
-	materialsService := materials.NewService(materialsRepo)
+	materialsService := materials.NewService(materialsRepo, authRepo)
 	materialsController := materials.NewController(materialsService)
 	materials.RegisterRoutes(app, materialsController)
 
@@ -181,6 +270,12 @@ func main() {
 	helpsController := helps.NewController(helpsService)
 	helps.RegisterRoutes(app, helpsController)
 
+	// Size Charts Feature
+	sizeChartsRepo := size_charts.NewRepository(database.DB)
+	sizeChartsService := size_charts.NewService(sizeChartsRepo, customersRepo)
+	sizeChartsController := size_charts.NewController(sizeChartsService)
+	size_charts.RegisterRoutes(app, sizeChartsController)
+
 	// 6. Cron Jobs
 	c := cron.New()
 	_, err := c.AddFunc("*/2 * * * *", func() {
@@ -189,6 +284,18 @@ func main() {
 	if err != nil {
 		log.Printf("Failed to add cron job: %v", err)
 	}
+	_, err = c.AddFunc("0 8 * * *", func() {
+		cronjobs.SendOrderReminders(database.DB)
+	})
+	if err != nil {
+		log.Printf("Failed to add cron job: %v", err)
+	}
+	_, err = c.AddFunc("0 7 * * *", func() {
+		cronjobs.SendLowStockAlerts(database.DB)
+	})
+	if err != nil {
+		log.Printf("Failed to add cron job: %v", err)
+	}
 	c.Start()
 	defer c.Stop()
 
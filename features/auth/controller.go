@@ -19,7 +19,7 @@ type Controller struct {
 func NewController(service Service) *Controller {
 	return &Controller{
 		service:  service,
-		validate: validator.New(),
+		validate: utils.NewValidator(),
 	}
 }
 
@@ -313,6 +313,29 @@ func (c *Controller) UpdateName(ctx *fiber.Ctx) error {
 	return utils.SendSuccess(ctx, user, "name updated successfully")
 }
 
+func (c *Controller) UpdateSettings(ctx *fiber.Ctx) error {
+	var req UpdateSettingsRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "invalid request body")
+	}
+
+	if err := c.validate.Struct(req); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, utils.ParseValidationError(err))
+	}
+
+	token := ctx.Locals("user").(*jwt.Token)
+	claims := token.Claims.(jwt.MapClaims)
+	userIDFloat := claims["user_id"].(float64)
+	userID := uint(userIDFloat)
+
+	user, err := c.service.UpdateSettings(userID, req)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(ctx, user, "settings updated successfully")
+}
+
 func (c *Controller) GetProfile(ctx *fiber.Ctx) error {
 	token, ok := ctx.Locals("user").(*jwt.Token)
 	if !ok {
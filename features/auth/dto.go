@@ -1,5 +1,7 @@
 package auth
 
+import "github.com/TFX0019/api-go-gds/pkg/utils"
+
 type RegisterRequest struct {
 	Name            string `json:"name" validate:"required"`
 	Email           string `json:"email" validate:"required,email"`
@@ -45,19 +47,42 @@ type UpdateNameRequest struct {
 	Name string `json:"name" validate:"required"`
 }
 
+type UpdateSettingsRequest struct {
+	MeasurementUnit *string      `json:"measurement_unit" validate:"omitempty,oneof=cm in"`
+	HourlyRate      *utils.Money `json:"hourly_rate" validate:"omitempty,gte=0"`
+	Currency        *string      `json:"currency" validate:"omitempty,len=3"`
+	Locale          *string      `json:"locale" validate:"omitempty,max=10"`
+	BusinessName    *string      `json:"business_name" validate:"omitempty,max=120"`
+	BusinessAddress *string      `json:"business_address" validate:"omitempty,max=255"`
+	BusinessPhone   *string      `json:"business_phone" validate:"omitempty,max=40"`
+	BusinessEmail   *string      `json:"business_email" validate:"omitempty,email"`
+	BusinessTaxID   *string      `json:"business_tax_id" validate:"omitempty,max=40"`
+	DueReminderDays *int         `json:"due_reminder_days" validate:"omitempty,min=0,max=30"`
+}
+
 type UserResponse struct {
-	ID            uint     `json:"id"`
-	Name          string   `json:"name"`
-	Email         string   `json:"email"`
-	CreatedAt     string   `json:"created_at"`
-	UpdatedAt     string   `json:"updated_at"`
-	Avatar        *string  `json:"avatar"`
-	IsPro         bool     `json:"is_pro"`
-	Plan          string   `json:"plan"`
-	MaxCustomers  int      `json:"max_customers"`
-	MaxProducts   int      `json:"max_products"`
-	MaxMaterials  int      `json:"max_materials"`
-	MaxTasks      int      `json:"max_tasks"`
-	WalletBalance float64  `json:"wallet_balance"`
-	Roles         []string `json:"roles"`
+	ID              uint        `json:"id"`
+	Name            string      `json:"name"`
+	Email           string      `json:"email"`
+	CreatedAt       string      `json:"created_at"`
+	UpdatedAt       string      `json:"updated_at"`
+	Avatar          *string     `json:"avatar"`
+	IsPro           bool        `json:"is_pro"`
+	Plan            string      `json:"plan"`
+	MaxCustomers    int         `json:"max_customers"`
+	MaxProducts     int         `json:"max_products"`
+	MaxMaterials    int         `json:"max_materials"`
+	MaxTasks        int         `json:"max_tasks"`
+	WalletBalance   float64     `json:"wallet_balance"`
+	Roles           []string    `json:"roles"`
+	MeasurementUnit string      `json:"measurement_unit"`
+	HourlyRate      utils.Money `json:"hourly_rate"`
+	Currency        string      `json:"currency"`
+	Locale          string      `json:"locale"`
+	BusinessName    string      `json:"business_name"`
+	BusinessAddress string      `json:"business_address"`
+	BusinessPhone   string      `json:"business_phone"`
+	BusinessEmail   string      `json:"business_email"`
+	BusinessTaxID   string      `json:"business_tax_id"`
+	DueReminderDays int         `json:"due_reminder_days"`
 }
@@ -5,6 +5,7 @@ import (
 
 	"github.com/TFX0019/api-go-gds/features/subscriptions"
 	"github.com/TFX0019/api-go-gds/features/wallets"
+	"github.com/TFX0019/api-go-gds/pkg/utils"
 	"gorm.io/gorm"
 )
 
@@ -35,6 +36,16 @@ type User struct {
 	ResetCode         string
 	ResetCodeExpiry   time.Time
 	Avatar            *string
+	MeasurementUnit   string                     `gorm:"type:varchar(10);not null;default:'cm'"`    // Display unit for customer measurements
+	HourlyRate        utils.Money                `gorm:"type:numeric;not null;default:0"`           // Labor cost per hour of logged time
+	Currency          string                     `gorm:"type:varchar(3);not null;default:'USD'"`    // Currency new orders and materials are priced in
+	Locale            string                     `gorm:"type:varchar(10);not null;default:'es-MX'"` // Number format of documents and public pages
+	BusinessName      string                     `gorm:"type:text"`                                 // Shown on quotes and invoices instead of the user's name
+	BusinessAddress   string                     `gorm:"type:text"`
+	BusinessPhone     string                     `gorm:"type:text"`
+	BusinessEmail     string                     `gorm:"type:text"`
+	BusinessTaxID     string                     `gorm:"type:text"`
+	DueReminderDays   int                        `gorm:"not null;default:2"` // Days before an order's due date to remind the user, 0 turns it off
 	Wallet            wallets.Wallet             `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
 	Subscription      subscriptions.Subscription `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
 	Roles             []Role                     `gorm:"many2many:user_roles;"`
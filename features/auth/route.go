@@ -22,5 +22,6 @@ func RegisterRoutes(app *fiber.App, controller *Controller) {
 	route.Post("/logout", controller.Logout)
 	route.Patch("/avatar", middleware.Protected(), controller.UpdateAvatar)
 	route.Patch("/name", middleware.Protected(), controller.UpdateName)
+	route.Patch("/settings", middleware.Protected(), controller.UpdateSettings)
 	route.Get("/profile", middleware.Protected(), controller.GetProfile)
 }
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/TFX0019/api-go-gds/features/plans"
@@ -20,6 +21,7 @@ type Service interface {
 	ResetPassword(req ResetPasswordRequest) error
 	UpdateAvatar(userID uint, avatarPath *string) (*UserResponse, error)
 	UpdateName(userID uint, name string) (*UserResponse, error)
+	UpdateSettings(userID uint, req UpdateSettingsRequest) (*UserResponse, error)
 	GetProfile(userID uint) (*UserResponse, error)
 	VerifyAccount(req VerifyAccountRequest, ip, userAgent string) (string, string, *UserResponse, error)
 	ResendVerificationCode(req ResendCodeRequest) error
@@ -362,6 +364,57 @@ func (s *service) UpdateName(userID uint, name string) (*UserResponse, error) {
 	return s.buildUserResponse(user)
 }
 
+func (s *service) UpdateSettings(userID uint, req UpdateSettingsRequest) (*UserResponse, error) {
+	user, err := s.repo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	if req.MeasurementUnit != nil {
+		user.MeasurementUnit = *req.MeasurementUnit
+	}
+	if req.HourlyRate != nil {
+		user.HourlyRate = *req.HourlyRate
+	}
+	if req.Currency != nil {
+		currency := strings.ToUpper(*req.Currency)
+		if !utils.IsCurrency(currency) {
+			return nil, errors.New("unsupported currency")
+		}
+		user.Currency = currency
+	}
+	if req.Locale != nil {
+		if !utils.IsLocale(*req.Locale) {
+			return nil, errors.New("unsupported locale")
+		}
+		user.Locale = *req.Locale
+	}
+	if req.BusinessName != nil {
+		user.BusinessName = strings.TrimSpace(*req.BusinessName)
+	}
+	if req.BusinessAddress != nil {
+		user.BusinessAddress = strings.TrimSpace(*req.BusinessAddress)
+	}
+	if req.BusinessPhone != nil {
+		user.BusinessPhone = strings.TrimSpace(*req.BusinessPhone)
+	}
+	if req.BusinessEmail != nil {
+		user.BusinessEmail = strings.TrimSpace(*req.BusinessEmail)
+	}
+	if req.BusinessTaxID != nil {
+		user.BusinessTaxID = strings.TrimSpace(*req.BusinessTaxID)
+	}
+	if req.DueReminderDays != nil {
+		user.DueReminderDays = *req.DueReminderDays
+	}
+
+	if err := s.repo.UpdateUser(user); err != nil {
+		return nil, err
+	}
+
+	return s.buildUserResponse(user)
+}
+
 func (s *service) GetProfile(userID uint) (*UserResponse, error) {
 	user, err := s.repo.FindByID(userID)
 	if err != nil {
@@ -425,19 +478,29 @@ func (s *service) buildUserResponse(user *User) (*UserResponse, error) {
 	}
 
 	return &UserResponse{
-		ID:            user.ID,
-		Name:          user.Name,
-		Email:         user.Email,
-		Avatar:        user.Avatar,
-		CreatedAt:     user.CreatedAt.Format("2006-01-02 15:04:05"),
-		UpdatedAt:     user.UpdatedAt.Format("2006-01-02 15:04:05"),
-		IsPro:         isPro,
-		Plan:          planName,
-		MaxCustomers:  maxCustomers,
-		MaxProducts:   maxProducts,
-		MaxMaterials:  maxMaterials,
-		MaxTasks:      maxTasks,
-		WalletBalance: float64(user.Wallet.Balance),
-		Roles:         roles,
+		ID:              user.ID,
+		Name:            user.Name,
+		Email:           user.Email,
+		Avatar:          user.Avatar,
+		CreatedAt:       user.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:       user.UpdatedAt.Format("2006-01-02 15:04:05"),
+		IsPro:           isPro,
+		Plan:            planName,
+		MaxCustomers:    maxCustomers,
+		MaxProducts:     maxProducts,
+		MaxMaterials:    maxMaterials,
+		MaxTasks:        maxTasks,
+		WalletBalance:   float64(user.Wallet.Balance),
+		Roles:           roles,
+		MeasurementUnit: user.MeasurementUnit,
+		HourlyRate:      user.HourlyRate,
+		Currency:        user.Currency,
+		Locale:          user.Locale,
+		BusinessName:    user.BusinessName,
+		BusinessAddress: user.BusinessAddress,
+		BusinessPhone:   user.BusinessPhone,
+		BusinessEmail:   user.BusinessEmail,
+		BusinessTaxID:   user.BusinessTaxID,
+		DueReminderDays: user.DueReminderDays,
 	}, nil
 }
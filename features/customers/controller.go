@@ -1,9 +1,12 @@
 package customers
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"path/filepath"
+	"strings"
 
 	"github.com/TFX0019/api-go-gds/pkg/utils"
 	"github.com/go-playground/validator/v10"
@@ -12,6 +15,9 @@ import (
 	"github.com/google/uuid"
 )
 
+// maxImportSize caps the size of an uploaded import file.
+const maxImportSize = 5 << 20
+
 type Controller struct {
 	service  Service
 	validate *validator.Validate
@@ -99,7 +105,7 @@ func (c *Controller) GetByUserID(ctx *fiber.Ctx) error {
 		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
 	}
 
-	var query PaginationQuery
+	var query CustomerListQuery
 	if err := ctx.QueryParser(&query); err != nil {
 		return utils.SendError(ctx, fiber.StatusBadRequest, "invalid query parameters")
 	}
@@ -111,7 +117,11 @@ func (c *Controller) GetByUserID(ctx *fiber.Ctx) error {
 		query.Limit = 10
 	}
 
-	res, err := c.service.GetByUserID(userID, query.Page, query.Limit)
+	if err := c.validate.Struct(query); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, utils.ParseValidationError(err))
+	}
+
+	res, err := c.service.GetByUserID(userID, query)
 	if err != nil {
 		return utils.SendError(ctx, fiber.StatusInternalServerError, err.Error())
 	}
@@ -156,12 +166,445 @@ func (c *Controller) Delete(ctx *fiber.Ctx) error {
 	}
 
 	if err := c.service.Delete(id); err != nil {
+		if errors.Is(err, ErrCustomerHasOrders) {
+			return utils.SendError(ctx, fiber.StatusConflict, err.Error())
+		}
 		return utils.SendError(ctx, fiber.StatusInternalServerError, err.Error())
 	}
 
 	return utils.SendSuccess(ctx, nil, "customer deleted successfully")
 }
 
+func (c *Controller) GetMeasurementHistory(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	id := ctx.Params("id")
+	if id == "" {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "id required")
+	}
+
+	res, err := c.service.GetMeasurementHistory(userID, id)
+	if err != nil {
+		if errors.Is(err, ErrCustomerNotFound) {
+			return utils.SendError(ctx, fiber.StatusNotFound, err.Error())
+		}
+		return utils.SendError(ctx, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(ctx, res, "measurement history retrieved successfully")
+}
+
+func (c *Controller) GetMeasurementSnapshot(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	id := ctx.Params("id")
+	snapshotID := ctx.Params("snapshot_id")
+	if id == "" || snapshotID == "" {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "customer id and snapshot id required")
+	}
+
+	res, err := c.service.GetMeasurementSnapshot(userID, id, snapshotID)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusNotFound, err.Error())
+	}
+
+	return utils.SendSuccess(ctx, res, "measurement snapshot retrieved successfully")
+}
+
+func (c *Controller) DiffMeasurements(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	id := ctx.Params("id")
+	if id == "" {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "id required")
+	}
+
+	var query MeasurementDiffQuery
+	if err := ctx.QueryParser(&query); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "invalid query parameters")
+	}
+
+	if err := c.validate.Struct(query); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, utils.ParseValidationError(err))
+	}
+
+	res, err := c.service.DiffMeasurements(userID, id, query)
+	if err != nil {
+		if errors.Is(err, ErrCustomerNotFound) {
+			return utils.SendError(ctx, fiber.StatusNotFound, err.Error())
+		}
+		return utils.SendError(ctx, fiber.StatusBadRequest, err.Error())
+	}
+
+	return utils.SendSuccess(ctx, res, "measurement changes retrieved successfully")
+}
+
+func (c *Controller) GetMeasurementFields(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	res, err := c.service.GetMeasurementFields(userID)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(ctx, res, "measurement fields retrieved successfully")
+}
+
+func (c *Controller) CreateMeasurementField(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	var req CreateMeasurementFieldRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "invalid request body")
+	}
+
+	if err := c.validate.Struct(req); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, utils.ParseValidationError(err))
+	}
+
+	res, err := c.service.CreateMeasurementField(userID, req)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendCreated(ctx, res, "measurement field created successfully")
+}
+
+func (c *Controller) UpdateMeasurementField(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	fieldID := ctx.Params("field_id")
+	if fieldID == "" {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "field id required")
+	}
+
+	var req UpdateMeasurementFieldRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "invalid request body")
+	}
+
+	if err := c.validate.Struct(req); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, utils.ParseValidationError(err))
+	}
+
+	res, err := c.service.UpdateMeasurementField(userID, fieldID, req)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(ctx, res, "measurement field updated successfully")
+}
+
+func (c *Controller) DeleteMeasurementField(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	fieldID := ctx.Params("field_id")
+	if fieldID == "" {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "field id required")
+	}
+
+	if err := c.service.DeleteMeasurementField(userID, fieldID); err != nil {
+		return utils.SendError(ctx, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(ctx, nil, "measurement field deleted successfully")
+}
+
+func (c *Controller) GetProfile(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	id := ctx.Params("id")
+	if id == "" {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "id required")
+	}
+
+	res, err := c.service.GetProfile(userID, id)
+	if err != nil {
+		if errors.Is(err, ErrCustomerNotFound) {
+			return utils.SendError(ctx, fiber.StatusNotFound, err.Error())
+		}
+		return utils.SendError(ctx, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(ctx, res, "customer profile retrieved successfully")
+}
+
+func (c *Controller) GetNotes(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	id := ctx.Params("id")
+	if id == "" {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "id required")
+	}
+
+	res, err := c.service.GetNotes(userID, id)
+	if err != nil {
+		if errors.Is(err, ErrCustomerNotFound) {
+			return utils.SendError(ctx, fiber.StatusNotFound, err.Error())
+		}
+		return utils.SendError(ctx, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(ctx, res, "customer notes retrieved successfully")
+}
+
+func (c *Controller) CreateNote(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	id := ctx.Params("id")
+	if id == "" {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "id required")
+	}
+
+	var req CustomerNoteRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "invalid request body")
+	}
+
+	if err := c.validate.Struct(req); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, utils.ParseValidationError(err))
+	}
+
+	res, err := c.service.CreateNote(userID, id, req)
+	if err != nil {
+		if errors.Is(err, ErrCustomerNotFound) {
+			return utils.SendError(ctx, fiber.StatusNotFound, err.Error())
+		}
+		return utils.SendError(ctx, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendCreated(ctx, res, "note created successfully")
+}
+
+func (c *Controller) UpdateNote(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	id := ctx.Params("id")
+	noteID := ctx.Params("note_id")
+	if id == "" || noteID == "" {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "customer id and note id required")
+	}
+
+	var req CustomerNoteRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "invalid request body")
+	}
+
+	if err := c.validate.Struct(req); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, utils.ParseValidationError(err))
+	}
+
+	res, err := c.service.UpdateNote(userID, id, noteID, req)
+	if err != nil {
+		if errors.Is(err, ErrCustomerNotFound) || errors.Is(err, ErrNoteNotFound) {
+			return utils.SendError(ctx, fiber.StatusNotFound, err.Error())
+		}
+		return utils.SendError(ctx, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(ctx, res, "note updated successfully")
+}
+
+func (c *Controller) DeleteNote(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	id := ctx.Params("id")
+	noteID := ctx.Params("note_id")
+	if id == "" || noteID == "" {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "customer id and note id required")
+	}
+
+	if err := c.service.DeleteNote(userID, id, noteID); err != nil {
+		if errors.Is(err, ErrCustomerNotFound) || errors.Is(err, ErrNoteNotFound) {
+			return utils.SendError(ctx, fiber.StatusNotFound, err.Error())
+		}
+		return utils.SendError(ctx, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(ctx, nil, "note deleted successfully")
+}
+
+func (c *Controller) FindDuplicates(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	var query DuplicatesQuery
+	if err := ctx.QueryParser(&query); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "invalid query parameters")
+	}
+
+	if err := c.validate.Struct(query); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, utils.ParseValidationError(err))
+	}
+
+	res, err := c.service.FindDuplicates(userID, query)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(ctx, res, "duplicate customers retrieved successfully")
+}
+
+func (c *Controller) Merge(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	id := ctx.Params("id")
+	if id == "" {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "id required")
+	}
+
+	var req MergeCustomersRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "invalid request body")
+	}
+
+	if err := c.validate.Struct(req); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, utils.ParseValidationError(err))
+	}
+
+	res, err := c.service.Merge(userID, id, req)
+	if err != nil {
+		if errors.Is(err, ErrCustomerNotFound) {
+			return utils.SendError(ctx, fiber.StatusNotFound, err.Error())
+		}
+		return utils.SendError(ctx, fiber.StatusBadRequest, err.Error())
+	}
+
+	return utils.SendSuccess(ctx, res, "customers merged successfully")
+}
+
+func (c *Controller) Import(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	var req ImportCustomersRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "invalid request body")
+	}
+
+	file, err := ctx.FormFile("file")
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "file required")
+	}
+	if file.Size > maxImportSize {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "file too large")
+	}
+
+	// The format is inferred from the file extension when not given
+	if req.Format == "" {
+		switch strings.ToLower(filepath.Ext(file.Filename)) {
+		case ".vcf", ".vcard":
+			req.Format = FormatVCard
+		default:
+			req.Format = FormatCSV
+		}
+	}
+
+	if err := c.validate.Struct(req); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, utils.ParseValidationError(err))
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "failed to read file")
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "failed to read file")
+	}
+
+	res, err := c.service.Import(userID, req, data)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, err.Error())
+	}
+
+	message := "customers imported successfully"
+	if req.DryRun {
+		message = "import preview generated successfully"
+	}
+	return utils.SendSuccess(ctx, res, message)
+}
+
+func (c *Controller) Export(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	var query ExportQuery
+	if err := ctx.QueryParser(&query); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "invalid query parameters")
+	}
+
+	if err := c.validate.Struct(query); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, utils.ParseValidationError(err))
+	}
+
+	if query.Format == "" {
+		query.Format = FormatCSV
+	}
+
+	data, err := c.service.Export(userID, query.Format)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusInternalServerError, err.Error())
+	}
+
+	if query.Format == FormatVCard {
+		ctx.Set(fiber.HeaderContentType, "text/vcard; charset=utf-8")
+		ctx.Set(fiber.HeaderContentDisposition, `attachment; filename="customers.vcf"`)
+	} else {
+		ctx.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+		ctx.Set(fiber.HeaderContentDisposition, `attachment; filename="customers.csv"`)
+	}
+
+	return ctx.Send(data)
+}
+
 func getUserIDFromToken(ctx *fiber.Ctx) (string, error) {
 	// Inspect how middleware stores user
 	userToken := ctx.Locals("user")
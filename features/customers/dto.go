@@ -1,5 +1,9 @@
 package customers
 
+import (
+	"github.com/TFX0019/api-go-gds/pkg/utils"
+)
+
 type CreateCustomerRequest struct {
 	Name             string   `form:"name" validate:"required"`
 	Phone            string   `form:"phone"`
@@ -21,6 +25,8 @@ type CreateCustomerRequest struct {
 	HemWidth         *float64 `form:"hem_width"`
 	SleeveLength     *float64 `form:"sleeve_length"`
 	CuffSize         *float64 `form:"cuff_size"`
+	// JSON object mapping custom measurement field IDs to values
+	CustomMeasurements string `form:"custom_measurements"`
 }
 
 type UpdateCustomerRequest struct {
@@ -44,6 +50,8 @@ type UpdateCustomerRequest struct {
 	HemWidth         *float64 `form:"hem_width"`
 	SleeveLength     *float64 `form:"sleeve_length"`
 	CuffSize         *float64 `form:"cuff_size"`
+	// JSON object mapping custom measurement field IDs to values
+	CustomMeasurements string `form:"custom_measurements"`
 }
 
 type PaginationQuery struct {
@@ -51,32 +59,209 @@ type PaginationQuery struct {
 	Limit int `query:"limit" validate:"min=1,max=100"`
 }
 
+type CustomerListQuery struct {
+	Page             int    `query:"page" validate:"min=1"`
+	Limit            int    `query:"limit" validate:"min=1,max=100"`
+	Search           string `query:"q"` // Matches name, phone and email ignoring accents
+	HasPendingOrders *bool  `query:"has_pending_orders"`
+	UsesStandardSize *bool  `query:"uses_standard_size"`
+	Sort             string `query:"sort" validate:"omitempty,oneof=name created_at last_order_date"`
+	Order            string `query:"order" validate:"omitempty,oneof=asc desc"`
+}
+
 type CustomerResponse struct {
-	ID               string   `json:"id"`
-	UserID           string   `json:"user_id"`
-	AvatarURL        string   `json:"avatar_url"`
-	Name             string   `json:"name"`
-	Phone            string   `json:"phone"`
-	Email            string   `json:"email"`
-	UsesStandardSize bool     `json:"uses_standard_size"`
-	StandardSize     string   `json:"standard_size"`
-	Back             *float64 `json:"back"`
-	Neck             *float64 `json:"neck"`
-	FrontSize        *float64 `json:"front_size"`
-	Armhole          *float64 `json:"armhole"`
-	BackSize         *float64 `json:"back_size"`
-	BustChest        *float64 `json:"bust_chest"`
-	Waist            *float64 `json:"waist"`
-	Hip              *float64 `json:"hip"`
-	RiseHeight       *float64 `json:"rise_height"`
-	SkirtLength      *float64 `json:"skirt_length"`
-	PantsLength      *float64 `json:"pants_length"`
-	KneeWidth        *float64 `json:"knee_width"`
-	HemWidth         *float64 `json:"hem_width"`
-	SleeveLength     *float64 `json:"sleeve_length"`
-	CuffSize         *float64 `json:"cuff_size"`
-	CreatedAt        string   `json:"created_at"`
-	UpdatedAt        string   `json:"updated_at"`
+	ID               string `json:"id"`
+	UserID           string `json:"user_id"`
+	AvatarURL        string `json:"avatar_url"`
+	Name             string `json:"name"`
+	Phone            string `json:"phone"`
+	Email            string `json:"email"`
+	UsesStandardSize bool   `json:"uses_standard_size"`
+	StandardSize     string `json:"standard_size"`
+	MeasurementUnit  string `json:"measurement_unit"`
+	MeasurementsResponse
+	CustomMeasurements []CustomMeasurementResponse `json:"custom_measurements"`
+	CreatedAt          string                      `json:"created_at"`
+	UpdatedAt          string                      `json:"updated_at"`
+}
+
+type MeasurementsResponse struct {
+	Back         *float64 `json:"back"`
+	Neck         *float64 `json:"neck"`
+	FrontSize    *float64 `json:"front_size"`
+	Armhole      *float64 `json:"armhole"`
+	BackSize     *float64 `json:"back_size"`
+	BustChest    *float64 `json:"bust_chest"`
+	Waist        *float64 `json:"waist"`
+	Hip          *float64 `json:"hip"`
+	RiseHeight   *float64 `json:"rise_height"`
+	SkirtLength  *float64 `json:"skirt_length"`
+	PantsLength  *float64 `json:"pants_length"`
+	KneeWidth    *float64 `json:"knee_width"`
+	HemWidth     *float64 `json:"hem_width"`
+	SleeveLength *float64 `json:"sleeve_length"`
+	CuffSize     *float64 `json:"cuff_size"`
+}
+
+type MeasurementSnapshotResponse struct {
+	ID              string `json:"id"`
+	CustomerID      string `json:"customer_id"`
+	MeasurementUnit string `json:"measurement_unit"`
+	MeasurementsResponse
+	CustomMeasurements []CustomMeasurementResponse `json:"custom_measurements"`
+	CreatedAt          string                      `json:"created_at"`
+}
+
+type CreateMeasurementFieldRequest struct {
+	Name         string `json:"name" validate:"required"`
+	Unit         string `json:"unit"`
+	Position     int    `json:"position" validate:"gte=0"`
+	GarmentGroup string `json:"garment_group"`
+}
+
+type UpdateMeasurementFieldRequest struct {
+	Name         string  `json:"name"`
+	Unit         string  `json:"unit"`
+	Position     *int    `json:"position" validate:"omitempty,gte=0"`
+	GarmentGroup *string `json:"garment_group"`
+}
+
+type MeasurementFieldResponse struct {
+	ID           string `json:"id,omitempty"`
+	Key          string `json:"key,omitempty"`
+	Name         string `json:"name"`
+	Unit         string `json:"unit"`
+	Position     int    `json:"position"`
+	GarmentGroup string `json:"garment_group"`
+	BuiltIn      bool   `json:"built_in"`
+}
+
+type CustomMeasurementResponse struct {
+	FieldID      string  `json:"field_id"`
+	Name         string  `json:"name"`
+	Unit         string  `json:"unit"`
+	Position     int     `json:"position"`
+	GarmentGroup string  `json:"garment_group"`
+	Value        float64 `json:"value"`
+}
+
+type MeasurementDiffQuery struct {
+	From string `query:"from" validate:"omitempty,uuid"`
+	To   string `query:"to" validate:"omitempty,uuid"`
+}
+
+type MeasurementChange struct {
+	Key        string   `json:"key"`
+	Name       string   `json:"name,omitempty"`
+	From       *float64 `json:"from"`
+	To         *float64 `json:"to"`
+	Difference *float64 `json:"difference"`
+}
+
+type MeasurementDiffResponse struct {
+	From    MeasurementSnapshotResponse `json:"from"`
+	To      MeasurementSnapshotResponse `json:"to"`
+	Changes []MeasurementChange         `json:"changes"`
+}
+
+type ImportCustomersRequest struct {
+	Format string `form:"format" validate:"omitempty,oneof=csv vcard"`
+	// JSON object mapping customer fields (name, phone, waist, a custom
+	// field id...) to CSV column headers
+	Mapping string `form:"mapping"`
+	// Unit of the imported measurements when the file has no measurement_unit column
+	Unit   string `form:"unit" validate:"omitempty,oneof=cm in"`
+	DryRun bool   `form:"dry_run"`
+}
+
+type ImportRowResult struct {
+	Row    int      `json:"row"`
+	Name   string   `json:"name"`
+	Status string   `json:"status"` // valid, imported, invalid or skipped
+	Errors []string `json:"errors,omitempty"`
+}
+
+type ImportResult struct {
+	DryRun    bool              `json:"dry_run"`
+	Format    string            `json:"format"`
+	TotalRows int               `json:"total_rows"`
+	Valid     int               `json:"valid"`
+	Imported  int               `json:"imported"`
+	Invalid   int               `json:"invalid"`
+	Skipped   int               `json:"skipped"`
+	Rows      []ImportRowResult `json:"rows"`
+}
+
+type ExportQuery struct {
+	Format string `query:"format" validate:"omitempty,oneof=csv vcard"`
+}
+
+type DuplicatesQuery struct {
+	// Minimum name similarity (0-1) for two customers to be reported
+	MinSimilarity float64 `query:"min_similarity" validate:"omitempty,gt=0,lte=1"`
+}
+
+type DuplicatePairResponse struct {
+	Customer       CustomerResponse `json:"customer"`
+	Duplicate      CustomerResponse `json:"duplicate"`
+	NameSimilarity float64          `json:"name_similarity"`
+	Reasons        []string         `json:"reasons"`
+}
+
+type MergeCustomersRequest struct {
+	DuplicateID string `json:"duplicate_id" validate:"required,uuid"`
+	// Fields whose value is taken from the duplicate; every other field keeps
+	// the surviving customer's value unless it is empty
+	UseDuplicate []string `json:"use_duplicate" validate:"omitempty,dive,oneof=name phone email avatar standard_size measurements"`
+}
+
+type CustomerNoteRequest struct {
+	Content string `json:"content" validate:"required"`
+}
+
+type CustomerNoteResponse struct {
+	ID         string `json:"id"`
+	CustomerID string `json:"customer_id"`
+	Content    string `json:"content"`
+	CreatedAt  string `json:"created_at"`
+	UpdatedAt  string `json:"updated_at"`
+}
+
+type CustomerOrderResponse struct {
+	ID         string      `json:"id"`
+	Name       string      `json:"name"`
+	Status     string      `json:"status"`
+	Total      utils.Money `json:"total"`
+	AmountPaid utils.Money `json:"amount_paid"`
+	DatePaid   *string     `json:"date_paid"`
+	CreatedAt  string      `json:"created_at"`
+}
+
+type CustomerStatsResponse struct {
+	OrderCount         int         `json:"order_count"`
+	TotalBilled        utils.Money `json:"total_billed"`
+	TotalPaid          utils.Money `json:"total_paid"`
+	OutstandingBalance utils.Money `json:"outstanding_balance"`
+	AverageOrderValue  utils.Money `json:"average_order_value"`
+	FirstOrderDate     *string     `json:"first_order_date"`
+	LastOrderDate      *string     `json:"last_order_date"`
+}
+
+type TimelineEntryResponse struct {
+	Type        string `json:"type"` // order, task or note
+	ID          string `json:"id"`
+	Date        string `json:"date"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Status      string `json:"status,omitempty"`
+	ProductID   string `json:"product_id,omitempty"`
+}
+
+type CustomerProfileResponse struct {
+	Customer       CustomerResponse                   `json:"customer"`
+	Stats          CustomerStatsResponse              `json:"stats"`
+	OrdersByStatus map[string][]CustomerOrderResponse `json:"orders_by_status"`
+	Timeline       []TimelineEntryResponse            `json:"timeline"`
 }
 
 type PaginatedResponse struct {
@@ -85,3 +270,43 @@ type PaginatedResponse struct {
 	Page  int                `json:"page"`
 	Limit int                `json:"limit"`
 }
+
+func (r CreateCustomerRequest) measurements() Measurements {
+	return Measurements{
+		Back:         r.Back,
+		Neck:         r.Neck,
+		FrontSize:    r.FrontSize,
+		Armhole:      r.Armhole,
+		BackSize:     r.BackSize,
+		BustChest:    r.BustChest,
+		Waist:        r.Waist,
+		Hip:          r.Hip,
+		RiseHeight:   r.RiseHeight,
+		SkirtLength:  r.SkirtLength,
+		PantsLength:  r.PantsLength,
+		KneeWidth:    r.KneeWidth,
+		HemWidth:     r.HemWidth,
+		SleeveLength: r.SleeveLength,
+		CuffSize:     r.CuffSize,
+	}
+}
+
+func (r UpdateCustomerRequest) measurements() Measurements {
+	return Measurements{
+		Back:         r.Back,
+		Neck:         r.Neck,
+		FrontSize:    r.FrontSize,
+		Armhole:      r.Armhole,
+		BackSize:     r.BackSize,
+		BustChest:    r.BustChest,
+		Waist:        r.Waist,
+		Hip:          r.Hip,
+		RiseHeight:   r.RiseHeight,
+		SkirtLength:  r.SkirtLength,
+		PantsLength:  r.PantsLength,
+		KneeWidth:    r.KneeWidth,
+		HemWidth:     r.HemWidth,
+		SleeveLength: r.SleeveLength,
+		CuffSize:     r.CuffSize,
+	}
+}
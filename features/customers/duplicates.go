@@ -0,0 +1,173 @@
+package customers
+
+import (
+	"errors"
+	"math"
+	"strconv"
+
+	"github.com/TFX0019/api-go-gds/pkg/utils"
+)
+
+// defaultNameSimilarity is the name similarity above which two customers are
+// reported as duplicates when their phone and email do not match.
+const defaultNameSimilarity = 0.6
+
+func (s *service) FindDuplicates(userID string, query DuplicatesQuery) ([]DuplicatePairResponse, error) {
+	uid, err := strconv.ParseUint(userID, 10, 32)
+	if err != nil {
+		return nil, errors.New("invalid user id")
+	}
+
+	minSimilarity := query.MinSimilarity
+	if minSimilarity == 0 {
+		minSimilarity = defaultNameSimilarity
+	}
+
+	matches, err := s.repo.FindDuplicates(uint(uid), minSimilarity)
+	if err != nil {
+		return nil, err
+	}
+
+	pairs := []DuplicatePairResponse{}
+	if len(matches) == 0 {
+		return pairs, nil
+	}
+
+	customers, _, err := s.repo.FindByUserID(userID, CustomerListQuery{}, -1, -1)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]Customer, len(customers))
+	for _, c := range customers {
+		byID[c.ID.String()] = c
+	}
+
+	unit := s.userUnit(uint(uid))
+	for _, m := range matches {
+		customer, ok := byID[m.CustomerID.String()]
+		duplicate, found := byID[m.DuplicateID.String()]
+		if !ok || !found {
+			continue
+		}
+
+		reasons := []string{}
+		if m.SamePhone {
+			reasons = append(reasons, "phone")
+		}
+		if m.SameEmail {
+			reasons = append(reasons, "email")
+		}
+		if m.NameSimilarity >= minSimilarity {
+			reasons = append(reasons, "name")
+		}
+
+		pairs = append(pairs, DuplicatePairResponse{
+			Customer:       mapToResponse(customer, unit),
+			Duplicate:      mapToResponse(duplicate, unit),
+			NameSimilarity: math.Round(m.NameSimilarity*100) / 100,
+			Reasons:        reasons,
+		})
+	}
+
+	return pairs, nil
+}
+
+// Merge folds the duplicate into the customer. Fields listed in UseDuplicate
+// take the duplicate's value; the others keep the customer's value and are
+// only filled from the duplicate when empty.
+func (s *service) Merge(userID, customerID string, req MergeCustomersRequest) (*CustomerResponse, error) {
+	if customerID == req.DuplicateID {
+		return nil, errors.New("a customer cannot be merged with itself")
+	}
+
+	survivor, err := s.findOwned(userID, customerID)
+	if err != nil {
+		return nil, err
+	}
+	duplicate, err := s.findOwned(userID, req.DuplicateID)
+	if err != nil {
+		return nil, err
+	}
+
+	useDuplicate := map[string]bool{}
+	for _, field := range req.UseDuplicate {
+		useDuplicate[field] = true
+	}
+
+	mergeString(&survivor.Name, duplicate.Name, useDuplicate["name"])
+	mergeString(&survivor.Phone, duplicate.Phone, useDuplicate["phone"])
+	mergeString(&survivor.Email, duplicate.Email, useDuplicate["email"])
+	mergeString(&survivor.AvatarURL, duplicate.AvatarURL, useDuplicate["avatar"])
+	if useDuplicate["standard_size"] || survivor.StandardSize == "" {
+		survivor.UsesStandardSize = duplicate.UsesStandardSize
+		survivor.StandardSize = duplicate.StandardSize
+	}
+
+	previous := survivor.Measurements
+	previousValues := customValuesMap(survivor.CustomValues)
+
+	values := mergeCustomValues(survivor, duplicate, useDuplicate["measurements"])
+	if useDuplicate["measurements"] {
+		survivor.Measurements = duplicate.Measurements
+	} else {
+		current, other := survivor.Measurements.Fields(), duplicate.Measurements.Fields()
+		for _, key := range MeasurementKeys {
+			if *current[key] == nil {
+				*current[key] = *other[key]
+			}
+		}
+	}
+	survivor.CustomValues = values
+
+	// The merged measurements become the latest entry of the combined history
+	var snapshot *MeasurementSnapshot
+	if !previous.Equal(survivor.Measurements) || !sameCustomValues(previousValues, customValuesMap(values), utils.UnitCentimeters) {
+		snapshot = &MeasurementSnapshot{
+			CustomerID:   survivor.ID,
+			UserID:       survivor.UserID,
+			Measurements: survivor.Measurements,
+			CustomValues: customValuesMap(values),
+		}
+	}
+
+	if err := s.repo.Merge(survivor, values, snapshot, duplicate.ID); err != nil {
+		return nil, err
+	}
+
+	res := mapToResponse(*survivor, s.userUnit(survivor.UserID))
+	return &res, nil
+}
+
+func mergeString(target *string, other string, useOther bool) {
+	if useOther || *target == "" {
+		*target = other
+	}
+}
+
+// mergeCustomValues returns the survivor's new custom values, pointing to the
+// survivor and keeping their field so they can be mapped to the response.
+func mergeCustomValues(survivor, duplicate *Customer, useDuplicate bool) []CustomerMeasurementValue {
+	values := []CustomerMeasurementValue{}
+	seen := map[string]bool{}
+
+	primary, secondary := survivor.CustomValues, duplicate.CustomValues
+	if useDuplicate {
+		primary, secondary = duplicate.CustomValues, nil
+	}
+
+	for _, list := range [][]CustomerMeasurementValue{primary, secondary} {
+		for _, v := range list {
+			if seen[v.FieldID.String()] {
+				continue
+			}
+			seen[v.FieldID.String()] = true
+			values = append(values, CustomerMeasurementValue{
+				CustomerID: survivor.ID,
+				FieldID:    v.FieldID,
+				Field:      v.Field,
+				Value:      v.Value,
+			})
+		}
+	}
+	return values
+}
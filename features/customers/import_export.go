@@ -0,0 +1,527 @@
+package customers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/mail"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/TFX0019/api-go-gds/pkg/utils"
+)
+
+const (
+	FormatCSV   = "csv"
+	FormatVCard = "vcard"
+)
+
+// Customer fields an import column can be mapped to, besides the built-in
+// measurement keys and the user's custom fields.
+var importBaseFields = []string{"name", "phone", "email", "uses_standard_size", "standard_size", "measurement_unit"}
+
+// importRow is one record of the uploaded file keyed by import target:
+// a base field, a measurement key or "custom:<field id>".
+type importRow struct {
+	line   int
+	values map[string]string
+}
+
+func (s *service) Import(userID string, req ImportCustomersRequest, data []byte) (*ImportResult, error) {
+	uid, err := strconv.ParseUint(userID, 10, 32)
+	if err != nil {
+		return nil, errors.New("invalid user id")
+	}
+
+	user, err := s.authRepo.FindByID(uint(uid))
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	fields, err := s.repo.FindMeasurementFieldsByUserID(uint(uid))
+	if err != nil {
+		return nil, err
+	}
+
+	format := req.Format
+	if format == "" {
+		format = FormatCSV
+	}
+
+	var rows []importRow
+	switch format {
+	case FormatVCard:
+		rows, err = parseVCardRows(data)
+	default:
+		rows, err = parseCSVRows(data, req.Mapping, fields)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	remaining, maxCustomers, err := s.remainingCustomers(user)
+	if err != nil {
+		return nil, err
+	}
+
+	defaultUnit := req.Unit
+	if defaultUnit == "" {
+		defaultUnit = unitOf(user)
+	}
+
+	result := &ImportResult{
+		DryRun:    req.DryRun,
+		Format:    format,
+		TotalRows: len(rows),
+		Rows:      []ImportRowResult{},
+	}
+
+	var customers []Customer
+	var accepted []int
+	for _, row := range rows {
+		customer, rowErrors := buildImportedCustomer(uint(uid), row, fields, defaultUnit)
+		rowResult := ImportRowResult{Row: row.line, Name: customer.Name, Errors: rowErrors}
+
+		switch {
+		case len(rowErrors) > 0:
+			rowResult.Status = "invalid"
+			result.Invalid++
+		case remaining != -1 && len(customers) >= remaining:
+			rowResult.Status = "skipped"
+			rowResult.Errors = []string{fmt.Sprintf("customer limit reached for your plan (%d)", maxCustomers)}
+			result.Skipped++
+		default:
+			rowResult.Status = "valid"
+			result.Valid++
+			customers = append(customers, customer)
+			accepted = append(accepted, len(result.Rows))
+		}
+		result.Rows = append(result.Rows, rowResult)
+	}
+
+	if req.DryRun || len(customers) == 0 {
+		return result, nil
+	}
+
+	if err := s.repo.CreateBatch(customers); err != nil {
+		return nil, err
+	}
+
+	for _, i := range accepted {
+		result.Rows[i].Status = "imported"
+	}
+	result.Imported = len(customers)
+
+	return result, nil
+}
+
+func (s *service) Export(userID string, format string) ([]byte, error) {
+	uid, err := strconv.ParseUint(userID, 10, 32)
+	if err != nil {
+		return nil, errors.New("invalid user id")
+	}
+
+	customers, _, err := s.repo.FindByUserID(userID, CustomerListQuery{Sort: "name", Order: "asc"}, -1, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	fields, err := s.repo.FindMeasurementFieldsByUserID(uint(uid))
+	if err != nil {
+		return nil, err
+	}
+
+	unit := s.userUnit(uint(uid))
+
+	if format == FormatVCard {
+		return exportVCards(customers, fields, unit)
+	}
+	return exportCSV(customers, fields, unit)
+}
+
+func parseCSVRows(data []byte, rawMapping string, fields []MeasurementField) ([]importRow, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = detectCSVDelimiter(data)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV file: %v", err)
+	}
+	if len(records) == 0 {
+		return nil, errors.New("the CSV file is empty")
+	}
+
+	columns, err := importColumns(records[0], rawMapping, fields)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := columns["name"]; !ok {
+		return nil, errors.New("no column is mapped to name")
+	}
+
+	rows := []importRow{}
+	for i, record := range records[1:] {
+		if isBlankRecord(record) {
+			continue
+		}
+
+		row := importRow{line: i + 2, values: map[string]string{}}
+		for target, index := range columns {
+			if index < len(record) {
+				row.values[target] = importCell(record[index])
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// importColumns resolves the column index of every import target. Without an
+// explicit mapping, headers are matched against field keys and names.
+func importColumns(header []string, rawMapping string, fields []MeasurementField) (map[string]int, error) {
+	headerIndex := make(map[string]int, len(header))
+	for i, h := range header {
+		headerIndex[normalizeHeader(importCell(h))] = i
+	}
+
+	targets := importTargets(fields)
+	columns := map[string]int{}
+
+	if rawMapping == "" {
+		for name, target := range targets {
+			if index, ok := headerIndex[name]; ok {
+				columns[target] = index
+			}
+		}
+		return columns, nil
+	}
+
+	var mapping map[string]string
+	if err := json.Unmarshal([]byte(rawMapping), &mapping); err != nil {
+		return nil, errors.New("mapping must be a JSON object of customer fields to column names")
+	}
+
+	for field, column := range mapping {
+		target, ok := targets[normalizeHeader(field)]
+		if !ok {
+			return nil, fmt.Errorf("unknown customer field %s in mapping", field)
+		}
+		index, ok := headerIndex[normalizeHeader(column)]
+		if !ok {
+			return nil, fmt.Errorf("column %s not found in the CSV file", column)
+		}
+		columns[target] = index
+	}
+	return columns, nil
+}
+
+// importTargets indexes every import target by the names it can be referred
+// to with: its key, its label and, for custom fields, their ID and name.
+func importTargets(fields []MeasurementField) map[string]string {
+	targets := map[string]string{}
+	for _, key := range importBaseFields {
+		targets[key] = key
+	}
+	for _, key := range MeasurementKeys {
+		targets[key] = key
+		targets[normalizeHeader(measurementLabels[key])] = key
+	}
+	for _, f := range fields {
+		target := "custom:" + f.ID.String()
+		targets[f.ID.String()] = target
+		if _, taken := targets[normalizeHeader(f.Name)]; !taken {
+			targets[normalizeHeader(f.Name)] = target
+		}
+	}
+	return targets
+}
+
+func normalizeHeader(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	s = strings.NewReplacer(" / ", "_", "/", "_", "-", "_", " ", "_").Replace(s)
+	return s
+}
+
+func detectCSVDelimiter(data []byte) rune {
+	firstLine := data
+	if i := bytes.IndexByte(data, '\n'); i != -1 {
+		firstLine = data[:i]
+	}
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		return ';'
+	}
+	return ','
+}
+
+func isBlankRecord(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func parseVCardRows(data []byte) ([]importRow, error) {
+	cards, err := utils.ParseVCards(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid vCard file: %v", err)
+	}
+
+	rows := make([]importRow, 0, len(cards))
+	for i, card := range cards {
+		row := importRow{line: i + 1, values: map[string]string{"name": card.FullName}}
+		if len(card.Phones) > 0 {
+			row.values["phone"] = card.Phones[0]
+		}
+		if len(card.Emails) > 0 {
+			row.values["email"] = card.Emails[0]
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func buildImportedCustomer(userID uint, row importRow, fields []MeasurementField, defaultUnit string) (Customer, []string) {
+	var rowErrors []string
+	customer := Customer{
+		UserID:       userID,
+		Name:         row.values["name"],
+		Phone:        row.values["phone"],
+		Email:        row.values["email"],
+		StandardSize: row.values["standard_size"],
+	}
+
+	if customer.Name == "" {
+		rowErrors = append(rowErrors, "name is required")
+	}
+	if customer.Email != "" {
+		if _, err := mail.ParseAddress(customer.Email); err != nil {
+			rowErrors = append(rowErrors, "email is not valid")
+		}
+	}
+
+	if raw := row.values["uses_standard_size"]; raw != "" {
+		value, ok := parseImportBool(raw)
+		if !ok {
+			rowErrors = append(rowErrors, "uses_standard_size must be yes or no")
+		}
+		customer.UsesStandardSize = value
+	}
+
+	unit := defaultUnit
+	if raw := strings.ToLower(row.values["measurement_unit"]); raw != "" {
+		if !utils.IsLengthUnit(raw) {
+			rowErrors = append(rowErrors, "measurement_unit must be cm or in")
+		} else {
+			unit = raw
+		}
+	}
+
+	measurements := customer.Measurements.Fields()
+	for _, key := range MeasurementKeys {
+		raw := row.values[key]
+		if raw == "" {
+			continue
+		}
+		value, err := parseImportNumber(raw)
+		if err != nil {
+			rowErrors = append(rowErrors, fmt.Sprintf("%s must be a positive number", key))
+			continue
+		}
+		value = utils.ToCentimeters(value, unit)
+		*measurements[key] = &value
+	}
+
+	for _, f := range fields {
+		raw := row.values["custom:"+f.ID.String()]
+		if raw == "" {
+			continue
+		}
+		value, err := parseImportNumber(raw)
+		if err != nil {
+			rowErrors = append(rowErrors, fmt.Sprintf("%s must be a positive number", f.Name))
+			continue
+		}
+		if utils.IsLengthUnit(f.Unit) {
+			value = utils.ToCentimeters(value, unit)
+		}
+		customer.CustomValues = append(customer.CustomValues, CustomerMeasurementValue{FieldID: f.ID, Value: value})
+	}
+
+	if !customer.Measurements.IsEmpty() || len(customer.CustomValues) > 0 {
+		customer.MeasurementHistory = []MeasurementSnapshot{{
+			UserID:       userID,
+			Measurements: customer.Measurements,
+			CustomValues: customValuesMap(customer.CustomValues),
+		}}
+	}
+
+	return customer, rowErrors
+}
+
+// parseImportNumber accepts both "70.5" and "70,5".
+func parseImportNumber(raw string) (float64, error) {
+	value, err := strconv.ParseFloat(strings.ReplaceAll(raw, ",", "."), 64)
+	if err != nil {
+		return 0, err
+	}
+	if value < 0 {
+		return 0, errors.New("negative value")
+	}
+	return value, nil
+}
+
+func parseImportBool(raw string) (bool, bool) {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "true", "yes", "y", "si", "sí", "s", "x", "1":
+		return true, true
+	case "false", "no", "n", "0":
+		return false, true
+	}
+	return false, false
+}
+
+func exportCSV(customers []Customer, fields []MeasurementField, unit string) ([]byte, error) {
+	sortFields(fields)
+
+	header := append([]string{}, importBaseFields...)
+	header = append(header, MeasurementKeys...)
+	for _, f := range fields {
+		header = append(header, csvText(f.Name))
+	}
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if err := writer.Write(header); err != nil {
+		return nil, err
+	}
+
+	for _, c := range customers {
+		record := []string{csvText(c.Name), csvText(c.Phone), csvText(c.Email), strconv.FormatBool(c.UsesStandardSize), csvText(c.StandardSize), unit}
+
+		measurements := fromCentimeters(c.Measurements, unit)
+		values := measurements.Fields()
+		for _, key := range MeasurementKeys {
+			record = append(record, formatExportNumber(*values[key]))
+		}
+
+		custom := customValuesForDisplay(customValuesMap(c.CustomValues), fieldsByID(fields), unit)
+		for _, f := range fields {
+			value, ok := custom[f.ID.String()]
+			if !ok {
+				record = append(record, "")
+				continue
+			}
+			record = append(record, formatExportNumber(&value))
+		}
+
+		if err := writer.Write(record); err != nil {
+			return nil, err
+		}
+	}
+
+	writer.Flush()
+	return buf.Bytes(), writer.Error()
+}
+
+// csvText keeps spreadsheets from reading a cell as a formula by prefixing
+// the characters that start one with a quote, which importCell removes.
+func csvText(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// importCell reads a cell back, dropping the quote csvText adds.
+func importCell(value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) > 1 && value[0] == '\'' && strings.ContainsRune("=+-@\t\r", rune(value[1])) {
+		return strings.TrimSpace(value[1:])
+	}
+	return value
+}
+
+func exportVCards(customers []Customer, fields []MeasurementField, unit string) ([]byte, error) {
+	sortFields(fields)
+	byID := fieldsByID(fields)
+
+	var buf bytes.Buffer
+	for _, c := range customers {
+		card := utils.VCard{FullName: c.Name, Note: measurementSummary(c, fields, byID, unit)}
+		if c.Phone != "" {
+			card.Phones = []string{c.Phone}
+		}
+		if c.Email != "" {
+			card.Emails = []string{c.Email}
+		}
+		if err := utils.WriteVCard(&buf, card); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// measurementSummary describes the customer's size and measurements in the
+// note of their exported contact.
+func measurementSummary(c Customer, fields []MeasurementField, byID map[string]MeasurementField, unit string) string {
+	var parts []string
+	if c.UsesStandardSize && c.StandardSize != "" {
+		parts = append(parts, "Standard size: "+c.StandardSize)
+	}
+
+	measurements := fromCentimeters(c.Measurements, unit)
+	values := measurements.Fields()
+	for _, key := range MeasurementKeys {
+		if v := *values[key]; v != nil {
+			parts = append(parts, fmt.Sprintf("%s: %s %s", measurementLabels[key], formatExportNumber(v), unit))
+		}
+	}
+
+	custom := customValuesForDisplay(customValuesMap(c.CustomValues), byID, unit)
+	for _, f := range fields {
+		value, ok := custom[f.ID.String()]
+		if !ok {
+			continue
+		}
+		fieldUnit := f.Unit
+		if utils.IsLengthUnit(fieldUnit) {
+			fieldUnit = unit
+		}
+		parts = append(parts, fmt.Sprintf("%s: %s %s", f.Name, formatExportNumber(&value), fieldUnit))
+	}
+
+	return strings.Join(parts, "\n")
+}
+
+func sortFields(fields []MeasurementField) {
+	sort.SliceStable(fields, func(i, j int) bool {
+		if fields[i].Position != fields[j].Position {
+			return fields[i].Position < fields[j].Position
+		}
+		return fields[i].Name < fields[j].Name
+	})
+}
+
+func fieldsByID(fields []MeasurementField) map[string]MeasurementField {
+	byID := make(map[string]MeasurementField, len(fields))
+	for _, f := range fields {
+		byID[f.ID.String()] = f
+	}
+	return byID
+}
+
+func formatExportNumber(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'f', -1, 64)
+}
@@ -6,34 +6,169 @@ import (
 	"github.com/google/uuid"
 )
 
+// Measurements holds the body measurements shared by a customer and each of
+// their measurement snapshots.
+type Measurements struct {
+	Back         *float64 `gorm:"type:numeric"`
+	Neck         *float64 `gorm:"type:numeric"`
+	FrontSize    *float64 `gorm:"type:numeric"`
+	Armhole      *float64 `gorm:"type:numeric"`
+	BackSize     *float64 `gorm:"type:numeric"`
+	BustChest    *float64 `gorm:"type:numeric"`
+	Waist        *float64 `gorm:"type:numeric"`
+	Hip          *float64 `gorm:"type:numeric"`
+	RiseHeight   *float64 `gorm:"type:numeric"`
+	SkirtLength  *float64 `gorm:"type:numeric"`
+	PantsLength  *float64 `gorm:"type:numeric"`
+	KneeWidth    *float64 `gorm:"type:numeric"`
+	HemWidth     *float64 `gorm:"type:numeric"`
+	SleeveLength *float64 `gorm:"type:numeric"`
+	CuffSize     *float64 `gorm:"type:numeric"`
+}
+
+// MeasurementKeys lists the measurement names in the order they are presented.
+var MeasurementKeys = []string{
+	"back", "neck", "front_size", "armhole", "back_size", "bust_chest", "waist", "hip",
+	"rise_height", "skirt_length", "pants_length", "knee_width", "hem_width", "sleeve_length", "cuff_size",
+}
+
+var measurementLabels = map[string]string{
+	"back":          "Back",
+	"neck":          "Neck",
+	"front_size":    "Front size",
+	"armhole":       "Armhole",
+	"back_size":     "Back size",
+	"bust_chest":    "Bust / chest",
+	"waist":         "Waist",
+	"hip":           "Hip",
+	"rise_height":   "Rise height",
+	"skirt_length":  "Skirt length",
+	"pants_length":  "Pants length",
+	"knee_width":    "Knee width",
+	"hem_width":     "Hem width",
+	"sleeve_length": "Sleeve length",
+	"cuff_size":     "Cuff size",
+}
+
+// Fields returns a pointer to every measurement keyed by its JSON name.
+func (m *Measurements) Fields() map[string]**float64 {
+	return map[string]**float64{
+		"back":          &m.Back,
+		"neck":          &m.Neck,
+		"front_size":    &m.FrontSize,
+		"armhole":       &m.Armhole,
+		"back_size":     &m.BackSize,
+		"bust_chest":    &m.BustChest,
+		"waist":         &m.Waist,
+		"hip":           &m.Hip,
+		"rise_height":   &m.RiseHeight,
+		"skirt_length":  &m.SkirtLength,
+		"pants_length":  &m.PantsLength,
+		"knee_width":    &m.KneeWidth,
+		"hem_width":     &m.HemWidth,
+		"sleeve_length": &m.SleeveLength,
+		"cuff_size":     &m.CuffSize,
+	}
+}
+
+// Equal reports whether both sets hold the same values.
+func (m Measurements) Equal(other Measurements) bool {
+	a, b := m.Fields(), other.Fields()
+	for _, key := range MeasurementKeys {
+		x, y := *a[key], *b[key]
+		if (x == nil) != (y == nil) {
+			return false
+		}
+		if x != nil && *x != *y {
+			return false
+		}
+	}
+	return true
+}
+
+// IsEmpty reports whether no measurement has been set.
+func (m Measurements) IsEmpty() bool {
+	return m.Equal(Measurements{})
+}
+
 type Customer struct {
-	ID               uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
-	UserID           uint      `gorm:"not null"`
-	AvatarURL        string    `gorm:"type:text"`
-	Name             string    `gorm:"type:text;not null"`
-	Phone            string    `gorm:"type:text"`
-	Email            string    `gorm:"type:text"`
-	UsesStandardSize bool      `gorm:"type:boolean;not null"`
-	StandardSize     string    `gorm:"type:text"`
-	Back             *float64  `gorm:"type:numeric"`
-	Neck             *float64  `gorm:"type:numeric"`
-	FrontSize        *float64  `gorm:"type:numeric"`
-	Armhole          *float64  `gorm:"type:numeric"`
-	BackSize         *float64  `gorm:"type:numeric"`
-	BustChest        *float64  `gorm:"type:numeric"`
-	Waist            *float64  `gorm:"type:numeric"`
-	Hip              *float64  `gorm:"type:numeric"`
-	RiseHeight       *float64  `gorm:"type:numeric"`
-	SkirtLength      *float64  `gorm:"type:numeric"`
-	PantsLength      *float64  `gorm:"type:numeric"`
-	KneeWidth        *float64  `gorm:"type:numeric"`
-	HemWidth         *float64  `gorm:"type:numeric"`
-	SleeveLength     *float64  `gorm:"type:numeric"`
-	CuffSize         *float64  `gorm:"type:numeric"`
-	CreatedAt        time.Time `gorm:"not null;default:now()"`
-	UpdatedAt        time.Time `gorm:"not null;default:now()"`
+	ID                 uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	UserID             uint      `gorm:"not null"`
+	AvatarURL          string    `gorm:"type:text"`
+	Name               string    `gorm:"type:text;not null"`
+	Phone              string    `gorm:"type:text"`
+	Email              string    `gorm:"type:text"`
+	UsesStandardSize   bool      `gorm:"type:boolean;not null"`
+	StandardSize       string    `gorm:"type:text"`
+	Measurements       `gorm:"embedded"`
+	CustomValues       []CustomerMeasurementValue `gorm:"foreignKey:CustomerID;constraint:OnDelete:CASCADE;"`
+	MeasurementHistory []MeasurementSnapshot      `gorm:"foreignKey:CustomerID;constraint:OnDelete:CASCADE;"`
+	Notes              []CustomerNote             `gorm:"foreignKey:CustomerID;constraint:OnDelete:CASCADE;"`
+	CreatedAt          time.Time                  `gorm:"not null;default:now()"`
+	UpdatedAt          time.Time                  `gorm:"not null;default:now()"`
 }
 
 func (Customer) TableName() string {
 	return "customers"
 }
+
+// MeasurementSnapshot is a dated copy of a customer's measurements, recorded
+// every time they change.
+type MeasurementSnapshot struct {
+	ID           uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	CustomerID   uuid.UUID `gorm:"type:uuid;not null;index"`
+	UserID       uint      `gorm:"not null"`
+	Measurements `gorm:"embedded"`
+	// Values of the user's custom measurement fields keyed by field ID
+	CustomValues map[string]float64 `gorm:"serializer:json"`
+	CreatedAt    time.Time          `gorm:"not null;default:now()"`
+}
+
+func (MeasurementSnapshot) TableName() string {
+	return "customer_measurements"
+}
+
+// MeasurementField is a user-defined measurement that complements the
+// built-in columns (shoulder width, inseam, head circumference...).
+type MeasurementField struct {
+	ID           uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	UserID       uint      `gorm:"not null;index"`
+	Name         string    `gorm:"type:text;not null"`
+	Unit         string    `gorm:"type:text;not null;default:'cm'"`
+	Position     int       `gorm:"not null;default:0"`
+	GarmentGroup string    `gorm:"type:text"`
+	CreatedAt    time.Time `gorm:"not null;default:now()"`
+	UpdatedAt    time.Time `gorm:"not null;default:now()"`
+}
+
+func (MeasurementField) TableName() string {
+	return "measurement_fields"
+}
+
+type CustomerMeasurementValue struct {
+	ID         uuid.UUID         `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	CustomerID uuid.UUID         `gorm:"type:uuid;not null;uniqueIndex:idx_customer_measurement_field"`
+	FieldID    uuid.UUID         `gorm:"type:uuid;not null;uniqueIndex:idx_customer_measurement_field"`
+	Field      *MeasurementField `gorm:"foreignKey:FieldID;constraint:OnDelete:CASCADE;"`
+	Value      float64           `gorm:"type:numeric;not null"`
+	CreatedAt  time.Time         `gorm:"not null;default:now()"`
+	UpdatedAt  time.Time         `gorm:"not null;default:now()"`
+}
+
+func (CustomerMeasurementValue) TableName() string {
+	return "customer_measurement_values"
+}
+
+// CustomerNote is a free-form note the user keeps about a customer.
+type CustomerNote struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	CustomerID uuid.UUID `gorm:"type:uuid;not null;index"`
+	UserID     uint      `gorm:"not null"`
+	Content    string    `gorm:"type:text;not null"`
+	CreatedAt  time.Time `gorm:"not null;default:now()"`
+	UpdatedAt  time.Time `gorm:"not null;default:now()"`
+}
+
+func (CustomerNote) TableName() string {
+	return "customer_notes"
+}
@@ -0,0 +1,214 @@
+package customers
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/TFX0019/api-go-gds/pkg/utils"
+)
+
+var ErrNoteNotFound = errors.New("note not found")
+
+func (s *service) GetProfile(userID, customerID string) (*CustomerProfileResponse, error) {
+	customer, err := s.findOwned(userID, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	orders, err := s.repo.FindOrders(customerID)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := s.repo.FindTasks(customerID)
+	if err != nil {
+		return nil, err
+	}
+	notes, err := s.repo.FindNotes(customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := &CustomerProfileResponse{
+		Customer:       mapToResponse(*customer, s.userUnit(customer.UserID)),
+		Stats:          orderStats(orders),
+		OrdersByStatus: map[string][]CustomerOrderResponse{},
+		Timeline:       []TimelineEntryResponse{},
+	}
+
+	for _, o := range orders {
+		profile.OrdersByStatus[o.Status] = append(profile.OrdersByStatus[o.Status], mapOrderToResponse(o))
+		profile.Timeline = append(profile.Timeline, TimelineEntryResponse{
+			Type:      "order",
+			ID:        o.ID.String(),
+			Date:      formatTime(o.CreatedAt),
+			Title:     o.Name,
+			Status:    o.Status,
+			ProductID: o.ID.String(),
+		})
+	}
+
+	for _, t := range tasks {
+		profile.Timeline = append(profile.Timeline, TimelineEntryResponse{
+			Type:        "task",
+			ID:          t.ID.String(),
+			Date:        formatTime(t.DateTime),
+			Title:       t.Name,
+			Description: t.Description,
+			Status:      t.Status,
+			ProductID:   t.ProductID.String(),
+		})
+	}
+
+	for _, n := range notes {
+		profile.Timeline = append(profile.Timeline, TimelineEntryResponse{
+			Type:  "note",
+			ID:    n.ID.String(),
+			Date:  formatTime(n.CreatedAt),
+			Title: n.Content,
+		})
+	}
+
+	// The date format sorts chronologically as text
+	sort.SliceStable(profile.Timeline, func(i, j int) bool {
+		return profile.Timeline[i].Date < profile.Timeline[j].Date
+	})
+
+	return profile, nil
+}
+
+func (s *service) GetNotes(userID, customerID string) ([]CustomerNoteResponse, error) {
+	if _, err := s.findOwned(userID, customerID); err != nil {
+		return nil, err
+	}
+
+	notes, err := s.repo.FindNotes(customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]CustomerNoteResponse, 0, len(notes))
+	for _, n := range notes {
+		res = append(res, mapNoteToResponse(n))
+	}
+	return res, nil
+}
+
+func (s *service) CreateNote(userID, customerID string, req CustomerNoteRequest) (*CustomerNoteResponse, error) {
+	customer, err := s.findOwned(userID, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	note := &CustomerNote{
+		CustomerID: customer.ID,
+		UserID:     customer.UserID,
+		Content:    strings.TrimSpace(req.Content),
+	}
+	if err := s.repo.CreateNote(note); err != nil {
+		return nil, err
+	}
+
+	res := mapNoteToResponse(*note)
+	return &res, nil
+}
+
+func (s *service) UpdateNote(userID, customerID, noteID string, req CustomerNoteRequest) (*CustomerNoteResponse, error) {
+	note, err := s.findOwnedNote(userID, customerID, noteID)
+	if err != nil {
+		return nil, err
+	}
+
+	note.Content = strings.TrimSpace(req.Content)
+	if err := s.repo.UpdateNote(note); err != nil {
+		return nil, err
+	}
+
+	res := mapNoteToResponse(*note)
+	return &res, nil
+}
+
+func (s *service) DeleteNote(userID, customerID, noteID string) error {
+	if _, err := s.findOwnedNote(userID, customerID, noteID); err != nil {
+		return err
+	}
+	return s.repo.DeleteNote(noteID)
+}
+
+func (s *service) findOwnedNote(userID, customerID, noteID string) (*CustomerNote, error) {
+	if _, err := s.findOwned(userID, customerID); err != nil {
+		return nil, err
+	}
+
+	note, err := s.repo.FindNoteByID(noteID)
+	if err != nil || note.CustomerID.String() != customerID {
+		return nil, ErrNoteNotFound
+	}
+	return note, nil
+}
+
+// orderStats summarizes the customer's orders; what they paid comes from the
+// payments recorded for each order, deposits included.
+func orderStats(orders []CustomerOrder) CustomerStatsResponse {
+	stats := CustomerStatsResponse{OrderCount: len(orders)}
+	if len(orders) == 0 {
+		return stats
+	}
+
+	billed := 0
+	for _, o := range orders {
+		// Cancelled orders are not billed
+		if o.Status == "cancelled" {
+			continue
+		}
+		billed++
+		stats.TotalBilled = stats.TotalBilled.Add(o.Total)
+		stats.TotalPaid = stats.TotalPaid.Add(utils.MinMoney(o.AmountPaid, o.Total))
+	}
+
+	stats.OutstandingBalance = stats.TotalBilled.Sub(stats.TotalPaid)
+	if billed > 0 {
+		stats.AverageOrderValue = stats.TotalBilled.Div(float64(billed)).Round()
+	}
+
+	// Orders are sorted by creation date
+	first := formatTime(orders[0].CreatedAt)
+	last := formatTime(orders[len(orders)-1].CreatedAt)
+	stats.FirstOrderDate = &first
+	stats.LastOrderDate = &last
+
+	return stats
+}
+
+func formatTime(t time.Time) string {
+	return t.Format("2006-01-02 15:04:05")
+}
+
+func mapOrderToResponse(o CustomerOrder) CustomerOrderResponse {
+	var datePaid *string
+	if o.DatePaid != nil {
+		d := formatTime(*o.DatePaid)
+		datePaid = &d
+	}
+
+	return CustomerOrderResponse{
+		ID:         o.ID.String(),
+		Name:       o.Name,
+		Status:     o.Status,
+		Total:      o.Total,
+		AmountPaid: o.AmountPaid,
+		DatePaid:   datePaid,
+		CreatedAt:  formatTime(o.CreatedAt),
+	}
+}
+
+func mapNoteToResponse(n CustomerNote) CustomerNoteResponse {
+	return CustomerNoteResponse{
+		ID:         n.ID.String(),
+		CustomerID: n.CustomerID.String(),
+		Content:    n.Content,
+		CreatedAt:  formatTime(n.CreatedAt),
+		UpdatedAt:  formatTime(n.UpdatedAt),
+	}
+}
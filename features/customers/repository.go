@@ -1,17 +1,43 @@
 package customers
 
 import (
+	"strings"
+	"time"
+
+	"github.com/TFX0019/api-go-gds/pkg/utils"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type Repository interface {
 	Create(customer *Customer) error
+	CreateBatch(customers []Customer) error
 	FindAll(limit, offset int) ([]Customer, int64, error)
 	FindByID(id string) (*Customer, error)
-	FindByUserID(userID string, limit, offset int) ([]Customer, int64, error)
+	FindByUserID(userID string, query CustomerListQuery, limit, offset int) ([]Customer, int64, error)
 	CountByUserID(userID uint) (int64, error)
 	Update(customer *Customer) error
+	UpdateWithMeasurements(customer *Customer, values []CustomerMeasurementValue, snapshot *MeasurementSnapshot) error
 	Delete(id string) error
+	HasPinnedSnapshots(customerID string) (bool, error)
+	FindMeasurementSnapshots(customerID string) ([]MeasurementSnapshot, error)
+	FindMeasurementSnapshotByID(id string) (*MeasurementSnapshot, error)
+	FindLatestMeasurementSnapshot(customerID string) (*MeasurementSnapshot, error)
+	CreateMeasurementField(field *MeasurementField) error
+	FindMeasurementFieldsByUserID(userID uint) ([]MeasurementField, error)
+	FindMeasurementFieldByID(id string) (*MeasurementField, error)
+	UpdateMeasurementField(field *MeasurementField) error
+	DeleteMeasurementField(id string) error
+	FindOrders(customerID string) ([]CustomerOrder, error)
+	FindTasks(customerID string) ([]CustomerTask, error)
+	CreateNote(note *CustomerNote) error
+	FindNotes(customerID string) ([]CustomerNote, error)
+	FindNoteByID(id string) (*CustomerNote, error)
+	UpdateNote(note *CustomerNote) error
+	DeleteNote(id string) error
+	FindDuplicates(userID uint, minSimilarity float64) ([]DuplicateMatch, error)
+	Merge(survivor *Customer, values []CustomerMeasurementValue, snapshot *MeasurementSnapshot, duplicateID uuid.UUID) error
 }
 
 type repository struct {
@@ -26,6 +52,10 @@ func (r *repository) Create(customer *Customer) error {
 	return r.db.Create(customer).Error
 }
 
+func (r *repository) CreateBatch(customers []Customer) error {
+	return r.db.Create(&customers).Error
+}
+
 func (r *repository) FindAll(limit, offset int) ([]Customer, int64, error) {
 	var customers []Customer
 	var total int64
@@ -35,7 +65,7 @@ func (r *repository) FindAll(limit, offset int) ([]Customer, int64, error) {
 		return nil, 0, err
 	}
 
-	err = r.db.Limit(limit).Offset(offset).Order("created_at desc").Find(&customers).Error
+	err = r.db.Preload("CustomValues.Field").Limit(limit).Offset(offset).Order("created_at desc").Find(&customers).Error
 	if err != nil {
 		return nil, 0, err
 	}
@@ -45,23 +75,85 @@ func (r *repository) FindAll(limit, offset int) ([]Customer, int64, error) {
 
 func (r *repository) FindByID(id string) (*Customer, error) {
 	var customer Customer
-	err := r.db.Where("id = ?", id).First(&customer).Error
+	err := r.db.Preload("CustomValues.Field").Where("id = ?", id).First(&customer).Error
 	if err != nil {
 		return nil, err
 	}
 	return &customer, nil
 }
 
-func (r *repository) FindByUserID(userID string, limit, offset int) ([]Customer, int64, error) {
+// openOrderStatuses are the product statuses of orders still being worked on.
+var openOrderStatuses = []string{"pending", "in_process", "complete"}
+
+func (r *repository) FindByUserID(userID string, query CustomerListQuery, limit, offset int) ([]Customer, int64, error) {
 	var customers []Customer
 	var total int64
 
-	err := r.db.Model(&Customer{}).Where("user_id = ?", userID).Count(&total).Error
+	db := r.db.Model(&Customer{}).Where("user_id = ?", userID)
+
+	search := strings.TrimSpace(query.Search)
+	if search != "" {
+		conditions := []string{
+			"unaccent(lower(name)) LIKE '%' || unaccent(lower(@search)) || '%'",
+			"word_similarity(unaccent(lower(@search)), unaccent(lower(name))) > 0.4",
+			"lower(email) LIKE '%' || lower(@search) || '%'",
+		}
+		args := map[string]interface{}{"search": search}
+
+		// Phones are compared on digits only so formatting does not matter
+		if digits := onlyDigits(search); digits != "" {
+			conditions = append(conditions, "regexp_replace(phone, '\\D', '', 'g') LIKE '%' || @digits || '%'")
+			args["digits"] = digits
+		}
+
+		db = db.Where("("+strings.Join(conditions, " OR ")+")", args)
+	}
+
+	if query.UsesStandardSize != nil {
+		db = db.Where("uses_standard_size = ?", *query.UsesStandardSize)
+	}
+
+	if query.HasPendingOrders != nil {
+		exists := "EXISTS (SELECT 1 FROM products WHERE products.client_id = customers.id AND products.status IN ?)"
+		if *query.HasPendingOrders {
+			db = db.Where(exists, openOrderStatuses)
+		} else {
+			db = db.Where("NOT "+exists, openOrderStatuses)
+		}
+	}
+
+	err := db.Count(&total).Error
 	if err != nil {
 		return nil, 0, err
 	}
 
-	err = r.db.Where("user_id = ?", userID).Limit(limit).Offset(offset).Order("created_at desc").Find(&customers).Error
+	direction := "desc"
+	if query.Order == "asc" {
+		direction = "asc"
+	}
+
+	switch query.Sort {
+	case "name":
+		if query.Order == "" {
+			direction = "asc"
+		}
+		db = db.Order("lower(name) " + direction)
+	case "last_order_date":
+		db = db.Order("(SELECT MAX(products.created_at) FROM products WHERE products.client_id = customers.id) " + direction + " NULLS LAST")
+	case "created_at":
+		db = db.Order("created_at " + direction)
+	default:
+		// Best matches first when searching, newest first otherwise
+		if search != "" {
+			db = db.Order(clause.OrderBy{Expression: clause.Expr{
+				SQL:  "word_similarity(unaccent(lower(?)), unaccent(lower(name))) DESC",
+				Vars: []interface{}{search},
+			}})
+		}
+		db = db.Order("created_at desc")
+	}
+
+	err = db.Preload("CustomValues.Field").Limit(limit).Offset(offset).Find(&customers).Error
 	if err != nil {
 		return nil, 0, err
 	}
@@ -69,6 +161,16 @@ func (r *repository) FindByUserID(userID string, limit, offset int) ([]Customer,
 	return customers, total, nil
 }
 
+func onlyDigits(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
 func (r *repository) CountByUserID(userID uint) (int64, error) {
 	var total int64
 	err := r.db.Model(&Customer{}).Where("user_id = ?", userID).Count(&total).Error
@@ -76,9 +178,262 @@ func (r *repository) CountByUserID(userID uint) (int64, error) {
 }
 
 func (r *repository) Update(customer *Customer) error {
-	return r.db.Save(customer).Error
+	return r.db.Omit(clause.Associations).Save(customer).Error
+}
+
+// UpdateWithMeasurements saves the customer, replaces its custom measurement
+// values when values is not nil and records the snapshot when given.
+func (r *repository) UpdateWithMeasurements(customer *Customer, values []CustomerMeasurementValue, snapshot *MeasurementSnapshot) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(customer).Error; err != nil {
+			return err
+		}
+
+		if values != nil {
+			if err := tx.Where("customer_id = ?", customer.ID).Delete(&CustomerMeasurementValue{}).Error; err != nil {
+				return err
+			}
+			if len(values) > 0 {
+				if err := tx.Omit("Field").Create(&values).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		if snapshot != nil {
+			return tx.Create(snapshot).Error
+		}
+		return nil
+	})
 }
 
 func (r *repository) Delete(id string) error {
 	return r.db.Delete(&Customer{}, "id = ?", id).Error
 }
+
+// HasPinnedSnapshots reports whether an order was made with one of the
+// customer's measurement snapshots.
+func (r *repository) HasPinnedSnapshots(customerID string) (bool, error) {
+	var pinned bool
+	err := r.db.Raw(`SELECT EXISTS (SELECT 1 FROM products
+		JOIN customer_measurements ON customer_measurements.id = products.measurement_snapshot_id
+		WHERE customer_measurements.customer_id = ?)`, customerID).Scan(&pinned).Error
+	return pinned, err
+}
+
+func (r *repository) FindMeasurementSnapshots(customerID string) ([]MeasurementSnapshot, error) {
+	var snapshots []MeasurementSnapshot
+	err := r.db.Where("customer_id = ?", customerID).Order("created_at desc").Find(&snapshots).Error
+	if err != nil {
+		return nil, err
+	}
+	return snapshots, nil
+}
+
+func (r *repository) FindMeasurementSnapshotByID(id string) (*MeasurementSnapshot, error) {
+	var snapshot MeasurementSnapshot
+	err := r.db.Where("id = ?", id).First(&snapshot).Error
+	if err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+func (r *repository) FindLatestMeasurementSnapshot(customerID string) (*MeasurementSnapshot, error) {
+	var snapshot MeasurementSnapshot
+	err := r.db.Where("customer_id = ?", customerID).Order("created_at desc").First(&snapshot).Error
+	if err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+func (r *repository) CreateMeasurementField(field *MeasurementField) error {
+	return r.db.Create(field).Error
+}
+
+func (r *repository) FindMeasurementFieldsByUserID(userID uint) ([]MeasurementField, error) {
+	var fields []MeasurementField
+	err := r.db.Where("user_id = ?", userID).Order("position asc, name asc").Find(&fields).Error
+	if err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+func (r *repository) FindMeasurementFieldByID(id string) (*MeasurementField, error) {
+	var field MeasurementField
+	err := r.db.Where("id = ?", id).First(&field).Error
+	if err != nil {
+		return nil, err
+	}
+	return &field, nil
+}
+
+func (r *repository) UpdateMeasurementField(field *MeasurementField) error {
+	return r.db.Save(field).Error
+}
+
+func (r *repository) DeleteMeasurementField(id string) error {
+	return r.db.Delete(&MeasurementField{}, "id = ?", id).Error
+}
+
+// CustomerOrder is a product ordered by a customer. It is read straight from
+// the products table, which belongs to a package depending on this one.
+type CustomerOrder struct {
+	ID         uuid.UUID
+	Name       string
+	Status     string
+	Total      utils.Money
+	AmountPaid utils.Money
+	DatePaid   *time.Time
+	CreatedAt  time.Time
+}
+
+// CustomerTask is a task linked to one of the customer's products.
+type CustomerTask struct {
+	ID          uuid.UUID
+	ProductID   uuid.UUID
+	Name        string
+	Description string
+	Status      string
+	DateTime    time.Time
+}
+
+func (r *repository) FindOrders(customerID string) ([]CustomerOrder, error) {
+	var orders []CustomerOrder
+	err := r.db.Table("products").
+		Select("id, name, status, total, date_paid, created_at, "+
+			"(SELECT COALESCE(SUM(amount), 0) FROM product_payments WHERE product_payments.product_id = products.id) AS amount_paid").
+		Where("client_id = ?", customerID).
+		Order("created_at asc").
+		Scan(&orders).Error
+	if err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+func (r *repository) FindTasks(customerID string) ([]CustomerTask, error) {
+	var tasks []CustomerTask
+	err := r.db.Table("tasks").
+		Select("tasks.id, tasks.product_id, tasks.name, tasks.description, tasks.status, tasks.date_time").
+		Joins("JOIN products ON products.id = tasks.product_id").
+		Where("products.client_id = ?", customerID).
+		Order("tasks.date_time asc").
+		Scan(&tasks).Error
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+func (r *repository) CreateNote(note *CustomerNote) error {
+	return r.db.Create(note).Error
+}
+
+func (r *repository) FindNotes(customerID string) ([]CustomerNote, error) {
+	var notes []CustomerNote
+	err := r.db.Where("customer_id = ?", customerID).Order("created_at desc").Find(&notes).Error
+	if err != nil {
+		return nil, err
+	}
+	return notes, nil
+}
+
+func (r *repository) FindNoteByID(id string) (*CustomerNote, error) {
+	var note CustomerNote
+	err := r.db.Where("id = ?", id).First(&note).Error
+	if err != nil {
+		return nil, err
+	}
+	return &note, nil
+}
+
+func (r *repository) UpdateNote(note *CustomerNote) error {
+	return r.db.Save(note).Error
+}
+
+func (r *repository) DeleteNote(id string) error {
+	return r.db.Delete(&CustomerNote{}, "id = ?", id).Error
+}
+
+// DuplicateMatch is a pair of customers of the same user that are likely the
+// same person. CustomerID is always the oldest of both.
+type DuplicateMatch struct {
+	CustomerID     uuid.UUID
+	DuplicateID    uuid.UUID
+	NameSimilarity float64
+	SamePhone      bool
+	SameEmail      bool
+}
+
+func (r *repository) FindDuplicates(userID uint, minSimilarity float64) ([]DuplicateMatch, error) {
+	var matches []DuplicateMatch
+
+	// Phones are compared on their last nine digits so country prefixes and
+	// formatting do not matter
+	err := r.db.Raw(`
+		SELECT * FROM (
+			SELECT a.id AS customer_id, b.id AS duplicate_id,
+				similarity(unaccent(lower(a.name)), unaccent(lower(b.name))) AS name_similarity,
+				(length(regexp_replace(coalesce(a.phone, ''), '\D', '', 'g')) >= 7
+					AND right(regexp_replace(coalesce(a.phone, ''), '\D', '', 'g'), 9) = right(regexp_replace(coalesce(b.phone, ''), '\D', '', 'g'), 9)) AS same_phone,
+				(trim(coalesce(a.email, '')) <> ''
+					AND lower(trim(a.email)) = lower(trim(coalesce(b.email, '')))) AS same_email
+			FROM customers a
+			JOIN customers b ON b.user_id = a.user_id AND (a.created_at, a.id) < (b.created_at, b.id)
+			WHERE a.user_id = ?
+		) pairs
+		WHERE name_similarity >= ? OR same_phone OR same_email
+		ORDER BY same_phone::int + same_email::int DESC, name_similarity DESC`,
+		userID, minSimilarity,
+	).Scan(&matches).Error
+	if err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
+// Merge saves the surviving customer, moves the duplicate's orders,
+// measurement history and notes to it and deletes the duplicate, all in one
+// transaction. Custom values are replaced when values is not nil.
+func (r *repository) Merge(survivor *Customer, values []CustomerMeasurementValue, snapshot *MeasurementSnapshot, duplicateID uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("UPDATE products SET client_id = ? WHERE client_id = ?", survivor.ID, duplicateID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&MeasurementSnapshot{}).Where("customer_id = ?", duplicateID).Update("customer_id", survivor.ID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&CustomerNote{}).Where("customer_id = ?", duplicateID).Update("customer_id", survivor.ID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&Customer{}, "id = ?", duplicateID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Omit(clause.Associations).Save(survivor).Error; err != nil {
+			return err
+		}
+
+		if values != nil {
+			if err := tx.Where("customer_id = ?", survivor.ID).Delete(&CustomerMeasurementValue{}).Error; err != nil {
+				return err
+			}
+			if len(values) > 0 {
+				if err := tx.Omit("Field").Create(&values).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		if snapshot != nil {
+			return tx.Create(snapshot).Error
+		}
+		return nil
+	})
+}
@@ -11,7 +11,23 @@ func RegisterRoutes(app fiber.Router, controller *Controller) {
 	route.Post("/", controller.Create)
 	route.Get("/", controller.GetAll)
 	route.Get("/user", controller.GetByUserID)
+	route.Post("/import", controller.Import)
+	route.Get("/export", controller.Export)
+	route.Get("/duplicates", controller.FindDuplicates)
+	route.Get("/measurement-fields", controller.GetMeasurementFields)
+	route.Post("/measurement-fields", controller.CreateMeasurementField)
+	route.Put("/measurement-fields/:field_id", controller.UpdateMeasurementField)
+	route.Delete("/measurement-fields/:field_id", controller.DeleteMeasurementField)
 	route.Get("/:id", controller.GetByID)
+	route.Get("/:id/profile", controller.GetProfile)
+	route.Get("/:id/notes", controller.GetNotes)
+	route.Post("/:id/notes", controller.CreateNote)
+	route.Put("/:id/notes/:note_id", controller.UpdateNote)
+	route.Delete("/:id/notes/:note_id", controller.DeleteNote)
+	route.Get("/:id/measurements", controller.GetMeasurementHistory)
+	route.Get("/:id/measurements/diff", controller.DiffMeasurements)
+	route.Get("/:id/measurements/:snapshot_id", controller.GetMeasurementSnapshot)
+	route.Post("/:id/merge", controller.Merge)
 	route.Put("/:id", controller.Update)
 	route.Delete("/:id", controller.Delete)
 }
@@ -1,23 +1,48 @@
 package customers
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"sort"
 	"strconv"
 
 	"github.com/TFX0019/api-go-gds/features/auth"
 	"github.com/TFX0019/api-go-gds/features/plans"
+	"github.com/TFX0019/api-go-gds/pkg/utils"
 )
 
 type Service interface {
 	Create(userID string, req CreateCustomerRequest, avatarURL string) (*CustomerResponse, error)
 	GetAll(page, limit int) (*PaginatedResponse, error)
 	GetByID(id string) (*CustomerResponse, error)
-	GetByUserID(userID string, page, limit int) (*PaginatedResponse, error)
+	GetByUserID(userID string, query CustomerListQuery) (*PaginatedResponse, error)
 	Update(id string, req UpdateCustomerRequest, avatarURL string) (*CustomerResponse, error)
 	Delete(id string) error
+	GetMeasurementHistory(userID, customerID string) ([]MeasurementSnapshotResponse, error)
+	GetMeasurementSnapshot(userID, customerID, snapshotID string) (*MeasurementSnapshotResponse, error)
+	DiffMeasurements(userID, customerID string, query MeasurementDiffQuery) (*MeasurementDiffResponse, error)
+	GetMeasurementFields(userID string) ([]MeasurementFieldResponse, error)
+	CreateMeasurementField(userID string, req CreateMeasurementFieldRequest) (*MeasurementFieldResponse, error)
+	UpdateMeasurementField(userID, fieldID string, req UpdateMeasurementFieldRequest) (*MeasurementFieldResponse, error)
+	DeleteMeasurementField(userID, fieldID string) error
+	Import(userID string, req ImportCustomersRequest, data []byte) (*ImportResult, error)
+	Export(userID string, format string) ([]byte, error)
+	GetProfile(userID, customerID string) (*CustomerProfileResponse, error)
+	GetNotes(userID, customerID string) ([]CustomerNoteResponse, error)
+	CreateNote(userID, customerID string, req CustomerNoteRequest) (*CustomerNoteResponse, error)
+	UpdateNote(userID, customerID, noteID string, req CustomerNoteRequest) (*CustomerNoteResponse, error)
+	DeleteNote(userID, customerID, noteID string) error
+	FindDuplicates(userID string, query DuplicatesQuery) ([]DuplicatePairResponse, error)
+	Merge(userID, customerID string, req MergeCustomersRequest) (*CustomerResponse, error)
 }
 
+var (
+	ErrCustomerNotFound  = errors.New("customer not found")
+	ErrCustomerHasOrders = errors.New("customer has orders made with their measurements")
+)
+
 type service struct {
 	repo      Repository
 	authRepo  auth.Repository
@@ -40,24 +65,12 @@ func (s *service) Create(userID string, req CreateCustomerRequest, avatarURL str
 		return nil, errors.New("user not found")
 	}
 
-	productID := user.Subscription.ProductID
-	if productID == "" {
-		productID = "free_tier"
-	}
-
-	plan, err := s.plansRepo.FindByProductID(productID)
+	remaining, maxCustomers, err := s.remainingCustomers(user)
 	if err != nil {
-		return nil, errors.New("plan not found")
+		return nil, err
 	}
-
-	if plan.MaxCustomers != -1 {
-		count, err := s.repo.CountByUserID(uint(uid))
-		if err != nil {
-			return nil, err
-		}
-		if int(count) >= plan.MaxCustomers {
-			return nil, fmt.Errorf("customer limit reached for your plan (%d)", plan.MaxCustomers)
-		}
+	if remaining == 0 {
+		return nil, fmt.Errorf("customer limit reached for your plan (%d)", maxCustomers)
 	}
 
 	customer := &Customer{
@@ -68,28 +81,33 @@ func (s *service) Create(userID string, req CreateCustomerRequest, avatarURL str
 		Email:            req.Email,
 		UsesStandardSize: req.UsesStandardSize,
 		StandardSize:     req.StandardSize,
-		Back:             req.Back,
-		Neck:             req.Neck,
-		FrontSize:        req.FrontSize,
-		Armhole:          req.Armhole,
-		BackSize:         req.BackSize,
-		BustChest:        req.BustChest,
-		Waist:            req.Waist,
-		Hip:              req.Hip,
-		RiseHeight:       req.RiseHeight,
-		SkirtLength:      req.SkirtLength,
-		PantsLength:      req.PantsLength,
-		KneeWidth:        req.KneeWidth,
-		HemWidth:         req.HemWidth,
-		SleeveLength:     req.SleeveLength,
-		CuffSize:         req.CuffSize,
+	}
+
+	// Measurements arrive in the user's display unit and are stored in cm
+	unit := unitOf(user)
+	customer.Measurements = toCentimeters(req.measurements(), unit)
+
+	values, fields, err := s.parseCustomMeasurements(uint(uid), req.CustomMeasurements, unit)
+	if err != nil {
+		return nil, err
+	}
+	customer.CustomValues = values
+
+	// The first set of measurements opens the customer's history
+	if !customer.Measurements.IsEmpty() || len(values) > 0 {
+		customer.MeasurementHistory = []MeasurementSnapshot{{
+			UserID:       customer.UserID,
+			Measurements: customer.Measurements,
+			CustomValues: customValuesMap(values),
+		}}
 	}
 
 	if err := s.repo.Create(customer); err != nil {
 		return nil, err
 	}
+	attachFields(customer.CustomValues, fields)
 
-	res := mapToResponse(*customer)
+	res := mapToResponse(*customer, unit)
 	return &res, nil
 }
 
@@ -102,7 +120,7 @@ func (s *service) GetAll(page, limit int) (*PaginatedResponse, error) {
 
 	var responses []CustomerResponse
 	for _, c := range customers {
-		responses = append(responses, mapToResponse(c))
+		responses = append(responses, mapToResponse(c, utils.UnitCentimeters))
 	}
 
 	return &PaginatedResponse{
@@ -118,20 +136,26 @@ func (s *service) GetByID(id string) (*CustomerResponse, error) {
 	if err != nil {
 		return nil, err
 	}
-	res := mapToResponse(*customer)
+	res := mapToResponse(*customer, s.userUnit(customer.UserID))
 	return &res, nil
 }
 
-func (s *service) GetByUserID(userID string, page, limit int) (*PaginatedResponse, error) {
+func (s *service) GetByUserID(userID string, query CustomerListQuery) (*PaginatedResponse, error) {
+	page, limit := query.Page, query.Limit
 	offset := (page - 1) * limit
-	customers, total, err := s.repo.FindByUserID(userID, limit, offset)
+	customers, total, err := s.repo.FindByUserID(userID, query, limit, offset)
 	if err != nil {
 		return nil, err
 	}
 
+	unit := utils.UnitCentimeters
+	if uid, err := strconv.ParseUint(userID, 10, 32); err == nil {
+		unit = s.userUnit(uint(uid))
+	}
+
 	var responses []CustomerResponse
 	for _, c := range customers {
-		responses = append(responses, mapToResponse(c))
+		responses = append(responses, mapToResponse(c, unit))
 	}
 
 	return &PaginatedResponse{
@@ -154,64 +178,629 @@ func (s *service) Update(id string, req UpdateCustomerRequest, avatarURL string)
 	customer.Email = req.Email
 	customer.UsesStandardSize = req.UsesStandardSize
 	customer.StandardSize = req.StandardSize
-	customer.Back = req.Back
-	customer.Neck = req.Neck
-	customer.FrontSize = req.FrontSize
-	customer.Armhole = req.Armhole
-	customer.BackSize = req.BackSize
-	customer.BustChest = req.BustChest
-	customer.Waist = req.Waist
-	customer.Hip = req.Hip
-	customer.RiseHeight = req.RiseHeight
-	customer.SkirtLength = req.SkirtLength
-	customer.PantsLength = req.PantsLength
-	customer.KneeWidth = req.KneeWidth
-	customer.HemWidth = req.HemWidth
-	customer.SleeveLength = req.SleeveLength
-	customer.CuffSize = req.CuffSize
+
+	unit := s.userUnit(customer.UserID)
+	measurements := toCentimeters(req.measurements(), unit)
+	if unit != utils.UnitCentimeters {
+		keepUnchanged(customer.Measurements, &measurements)
+	}
+	measurementsChanged := !customer.Measurements.Equal(measurements)
+	customer.Measurements = measurements
+
+	// Custom values are only replaced when sent, so older clients that do not
+	// know about them keep them intact
+	values, fields, err := s.parseCustomMeasurements(customer.UserID, req.CustomMeasurements, unit)
+	if err != nil {
+		return nil, err
+	}
+	if values != nil {
+		if unit != utils.UnitCentimeters {
+			keepUnchangedCustom(customer.CustomValues, values, fields)
+		}
+		if !sameCustomValues(customValuesMap(customer.CustomValues), customValuesMap(values), unit) {
+			measurementsChanged = true
+		}
+		customer.CustomValues = values
+	}
 
 	if avatarURL != "" {
 		customer.AvatarURL = avatarURL
 	}
 
-	if err := s.repo.Update(customer); err != nil {
+	var snapshot *MeasurementSnapshot
+	if measurementsChanged {
+		snapshot = &MeasurementSnapshot{
+			CustomerID:   customer.ID,
+			UserID:       customer.UserID,
+			Measurements: customer.Measurements,
+			CustomValues: customValuesMap(customer.CustomValues),
+		}
+	}
+
+	if values != nil {
+		for i := range values {
+			values[i].CustomerID = customer.ID
+		}
+	}
+
+	if err := s.repo.UpdateWithMeasurements(customer, values, snapshot); err != nil {
 		return nil, err
 	}
+	if values != nil {
+		attachFields(customer.CustomValues, fields)
+	}
 
-	res := mapToResponse(*customer)
+	res := mapToResponse(*customer, unit)
 	return &res, nil
 }
 
+// Delete removes the customer unless orders pin their measurements, which
+// must outlive them.
 func (s *service) Delete(id string) error {
+	pinned, err := s.repo.HasPinnedSnapshots(id)
+	if err != nil {
+		return err
+	}
+	if pinned {
+		return ErrCustomerHasOrders
+	}
 	return s.repo.Delete(id)
 }
 
-func mapToResponse(c Customer) CustomerResponse {
+func (s *service) GetMeasurementHistory(userID, customerID string) ([]MeasurementSnapshotResponse, error) {
+	customer, err := s.findOwned(userID, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots, err := s.repo.FindMeasurementSnapshots(customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	fields, err := s.measurementFieldsByID(customer.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	unit := s.userUnit(customer.UserID)
+	responses := []MeasurementSnapshotResponse{}
+	for _, snapshot := range snapshots {
+		responses = append(responses, mapSnapshotToResponse(snapshot, fields, unit))
+	}
+	return responses, nil
+}
+
+func (s *service) GetMeasurementSnapshot(userID, customerID, snapshotID string) (*MeasurementSnapshotResponse, error) {
+	customer, err := s.findOwned(userID, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, err := s.repo.FindMeasurementSnapshotByID(snapshotID)
+	if err != nil || snapshot.CustomerID.String() != customerID {
+		return nil, errors.New("measurement snapshot not found")
+	}
+
+	fields, err := s.measurementFieldsByID(customer.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := mapSnapshotToResponse(*snapshot, fields, s.userUnit(customer.UserID))
+	return &res, nil
+}
+
+func (s *service) DiffMeasurements(userID, customerID string, query MeasurementDiffQuery) (*MeasurementDiffResponse, error) {
+	customer, err := s.findOwned(userID, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots, err := s.repo.FindMeasurementSnapshots(customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Snapshots come newest first: compare against the latest one and the one
+	// right before it unless the caller picked them explicitly
+	toIndex := 0
+	if query.To != "" {
+		toIndex = indexOfSnapshot(snapshots, query.To)
+		if toIndex == -1 {
+			return nil, errors.New("measurement snapshot not found")
+		}
+	}
+
+	fromIndex := toIndex + 1
+	if query.From != "" {
+		fromIndex = indexOfSnapshot(snapshots, query.From)
+		if fromIndex == -1 {
+			return nil, errors.New("measurement snapshot not found")
+		}
+	}
+
+	if toIndex >= len(snapshots) || fromIndex >= len(snapshots) {
+		return nil, errors.New("not enough measurement history to compare")
+	}
+
+	fields, err := s.measurementFieldsByID(customer.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Differences are reported in the user's display unit
+	unit := s.userUnit(customer.UserID)
+	from, to := snapshots[fromIndex], snapshots[toIndex]
+	fromDisplay := fromCentimeters(from.Measurements, unit)
+	toDisplay := fromCentimeters(to.Measurements, unit)
+	fromCustom := customValuesForDisplay(from.CustomValues, fields, unit)
+	toCustom := customValuesForDisplay(to.CustomValues, fields, unit)
+	fromFields, toFields := fromDisplay.Fields(), toDisplay.Fields()
+
+	changes := []MeasurementChange{}
+	for _, key := range MeasurementKeys {
+		before, after := *fromFields[key], *toFields[key]
+		if (before == nil) == (after == nil) && (before == nil || *before == *after) {
+			continue
+		}
+
+		change := MeasurementChange{Key: key, From: before, To: after}
+		if before != nil && after != nil {
+			difference := *after - *before
+			change.Difference = &difference
+		}
+		changes = append(changes, change)
+	}
+
+	for _, fieldID := range customValueKeys(fromCustom, toCustom) {
+		before, hadBefore := fromCustom[fieldID]
+		after, hasAfter := toCustom[fieldID]
+		if hadBefore == hasAfter && before == after {
+			continue
+		}
+
+		change := MeasurementChange{Key: fieldID, Name: fields[fieldID].Name}
+		if hadBefore {
+			change.From = &before
+		}
+		if hasAfter {
+			change.To = &after
+		}
+		if hadBefore && hasAfter {
+			difference := after - before
+			change.Difference = &difference
+		}
+		changes = append(changes, change)
+	}
+
+	return &MeasurementDiffResponse{
+		From:    mapSnapshotToResponse(from, fields, unit),
+		To:      mapSnapshotToResponse(to, fields, unit),
+		Changes: changes,
+	}, nil
+}
+
+func (s *service) GetMeasurementFields(userID string) ([]MeasurementFieldResponse, error) {
+	uid, err := strconv.ParseUint(userID, 10, 32)
+	if err != nil {
+		return nil, errors.New("invalid user id")
+	}
+
+	// Built-in columns come first so clients can render every measurement
+	// from a single list
+	unit := s.userUnit(uint(uid))
+	responses := []MeasurementFieldResponse{}
+	for i, key := range MeasurementKeys {
+		responses = append(responses, MeasurementFieldResponse{
+			Key:      key,
+			Name:     measurementLabels[key],
+			Unit:     unit,
+			Position: i,
+			BuiltIn:  true,
+		})
+	}
+
+	fields, err := s.repo.FindMeasurementFieldsByUserID(uint(uid))
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range fields {
+		responses = append(responses, mapFieldToResponse(f, unit))
+	}
+	return responses, nil
+}
+
+func (s *service) CreateMeasurementField(userID string, req CreateMeasurementFieldRequest) (*MeasurementFieldResponse, error) {
+	uid, err := strconv.ParseUint(userID, 10, 32)
+	if err != nil {
+		return nil, errors.New("invalid user id")
+	}
+
+	field := &MeasurementField{
+		UserID:       uint(uid),
+		Name:         req.Name,
+		Unit:         normalizeFieldUnit(req.Unit),
+		Position:     req.Position,
+		GarmentGroup: req.GarmentGroup,
+	}
+
+	if err := s.repo.CreateMeasurementField(field); err != nil {
+		return nil, err
+	}
+
+	res := mapFieldToResponse(*field, s.userUnit(field.UserID))
+	return &res, nil
+}
+
+func (s *service) UpdateMeasurementField(userID, fieldID string, req UpdateMeasurementFieldRequest) (*MeasurementFieldResponse, error) {
+	field, err := s.findOwnedField(userID, fieldID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != "" {
+		field.Name = req.Name
+	}
+	if req.Unit != "" {
+		field.Unit = normalizeFieldUnit(req.Unit)
+	}
+	if req.Position != nil {
+		field.Position = *req.Position
+	}
+	if req.GarmentGroup != nil {
+		field.GarmentGroup = *req.GarmentGroup
+	}
+
+	if err := s.repo.UpdateMeasurementField(field); err != nil {
+		return nil, err
+	}
+
+	res := mapFieldToResponse(*field, s.userUnit(field.UserID))
+	return &res, nil
+}
+
+func (s *service) DeleteMeasurementField(userID, fieldID string) error {
+	if _, err := s.findOwnedField(userID, fieldID); err != nil {
+		return err
+	}
+	return s.repo.DeleteMeasurementField(fieldID)
+}
+
+func (s *service) findOwned(userID, customerID string) (*Customer, error) {
+	customer, err := s.repo.FindByID(customerID)
+	if err != nil {
+		return nil, ErrCustomerNotFound
+	}
+	if fmt.Sprintf("%d", customer.UserID) != userID {
+		return nil, ErrCustomerNotFound
+	}
+	return customer, nil
+}
+
+func (s *service) findOwnedField(userID, fieldID string) (*MeasurementField, error) {
+	field, err := s.repo.FindMeasurementFieldByID(fieldID)
+	if err != nil || fmt.Sprintf("%d", field.UserID) != userID {
+		return nil, errors.New("measurement field not found")
+	}
+	return field, nil
+}
+
+func (s *service) measurementFieldsByID(userID uint) (map[string]MeasurementField, error) {
+	fields, err := s.repo.FindMeasurementFieldsByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]MeasurementField, len(fields))
+	for _, f := range fields {
+		byID[f.ID.String()] = f
+	}
+	return byID, nil
+}
+
+// parseCustomMeasurements decodes the custom_measurements form value, a JSON
+// object keyed by field ID. It returns nil values when nothing was sent.
+func (s *service) parseCustomMeasurements(userID uint, raw string, unit string) ([]CustomerMeasurementValue, map[string]MeasurementField, error) {
+	if raw == "" {
+		return nil, nil, nil
+	}
+
+	var input map[string]float64
+	if err := json.Unmarshal([]byte(raw), &input); err != nil {
+		return nil, nil, errors.New("custom_measurements must be a JSON object of field ids to values")
+	}
+
+	fields, err := s.measurementFieldsByID(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	values := []CustomerMeasurementValue{}
+	for fieldID, value := range input {
+		field, ok := fields[fieldID]
+		if !ok {
+			return nil, nil, fmt.Errorf("measurement field %s not found", fieldID)
+		}
+		if utils.IsLengthUnit(field.Unit) {
+			value = utils.ToCentimeters(value, unit)
+		}
+		values = append(values, CustomerMeasurementValue{FieldID: field.ID, Value: value})
+	}
+	return values, fields, nil
+}
+
+func attachFields(values []CustomerMeasurementValue, fields map[string]MeasurementField) {
+	for i := range values {
+		if field, ok := fields[values[i].FieldID.String()]; ok {
+			values[i].Field = &field
+		}
+	}
+}
+
+func customValuesMap(values []CustomerMeasurementValue) map[string]float64 {
+	m := make(map[string]float64, len(values))
+	for _, v := range values {
+		m[v.FieldID.String()] = v.Value
+	}
+	return m
+}
+
+func sameCustomValues(a, b map[string]float64, unit string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key, value := range a {
+		other, ok := b[key]
+		if !ok || !sameMeasurement(value, other, unit) {
+			return false
+		}
+	}
+	return true
+}
+
+func customValueKeys(a, b map[string]float64) []string {
+	seen := map[string]bool{}
+	var keys []string
+	for _, m := range []map[string]float64{a, b} {
+		for key := range m {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// remainingCustomers returns how many customers the user's plan still allows,
+// -1 meaning unlimited, along with the plan limit.
+func (s *service) remainingCustomers(user *auth.User) (int, int, error) {
+	productID := user.Subscription.ProductID
+	if productID == "" {
+		productID = "free_tier"
+	}
+
+	plan, err := s.plansRepo.FindByProductID(productID)
+	if err != nil {
+		return 0, 0, errors.New("plan not found")
+	}
+
+	if plan.MaxCustomers == -1 {
+		return -1, -1, nil
+	}
+
+	count, err := s.repo.CountByUserID(user.ID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	remaining := plan.MaxCustomers - int(count)
+	if remaining < 0 {
+		remaining = 0
+	}
+	return remaining, plan.MaxCustomers, nil
+}
+
+func (s *service) userUnit(userID uint) string {
+	user, err := s.authRepo.FindByID(userID)
+	if err != nil {
+		return utils.UnitCentimeters
+	}
+	return unitOf(user)
+}
+
+func unitOf(user *auth.User) string {
+	if user.MeasurementUnit == "" {
+		return utils.UnitCentimeters
+	}
+	return user.MeasurementUnit
+}
+
+// normalizeFieldUnit stores every length field in cm, like the built-in
+// columns; any other unit (degrees, counts...) is kept as is.
+func normalizeFieldUnit(unit string) string {
+	if unit == "" || utils.IsLengthUnit(unit) {
+		return utils.UnitCentimeters
+	}
+	return unit
+}
+
+func convertMeasurements(m Measurements, convert func(float64) float64) Measurements {
+	converted := m
+	for _, value := range converted.Fields() {
+		if *value != nil {
+			v := convert(**value)
+			*value = &v
+		}
+	}
+	return converted
+}
+
+func toCentimeters(m Measurements, unit string) Measurements {
+	return convertMeasurements(m, func(v float64) float64 { return utils.ToCentimeters(v, unit) })
+}
+
+func fromCentimeters(m Measurements, unit string) Measurements {
+	return convertMeasurements(m, func(v float64) float64 { return utils.FromCentimeters(v, unit) })
+}
+
+// sameMeasurement compares stored values, ignoring the drift caused by
+// displaying inches with two decimals and converting them back.
+func sameMeasurement(a, b float64, unit string) bool {
+	if unit == utils.UnitCentimeters {
+		return a == b
+	}
+	return math.Abs(a-b) < 0.013
+}
+
+// keepUnchanged restores previous values that only differ from next by the
+// unit conversion drift, so resubmitting a form does not record a change.
+func keepUnchanged(previous Measurements, next *Measurements) {
+	before, after := previous.Fields(), next.Fields()
+	for key, value := range after {
+		old := *before[key]
+		if *value != nil && old != nil && sameMeasurement(*old, **value, utils.UnitInches) {
+			*value = old
+		}
+	}
+}
+
+// keepUnchangedCustom does the same for the custom values measured in a
+// length unit, the only ones converted.
+func keepUnchangedCustom(previous, next []CustomerMeasurementValue, fields map[string]MeasurementField) {
+	before := customValuesMap(previous)
+	for i, v := range next {
+		key := v.FieldID.String()
+		old, ok := before[key]
+		if ok && utils.IsLengthUnit(fields[key].Unit) && sameMeasurement(old, v.Value, utils.UnitInches) {
+			next[i].Value = old
+		}
+	}
+}
+
+func customValuesForDisplay(values map[string]float64, fields map[string]MeasurementField, unit string) map[string]float64 {
+	converted := make(map[string]float64, len(values))
+	for fieldID, value := range values {
+		if field, ok := fields[fieldID]; ok && utils.IsLengthUnit(field.Unit) {
+			value = utils.FromCentimeters(value, unit)
+		}
+		converted[fieldID] = value
+	}
+	return converted
+}
+
+func indexOfSnapshot(snapshots []MeasurementSnapshot, id string) int {
+	for i, snapshot := range snapshots {
+		if snapshot.ID.String() == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func mapMeasurementsToResponse(m Measurements) MeasurementsResponse {
+	return MeasurementsResponse{
+		Back:         m.Back,
+		Neck:         m.Neck,
+		FrontSize:    m.FrontSize,
+		Armhole:      m.Armhole,
+		BackSize:     m.BackSize,
+		BustChest:    m.BustChest,
+		Waist:        m.Waist,
+		Hip:          m.Hip,
+		RiseHeight:   m.RiseHeight,
+		SkirtLength:  m.SkirtLength,
+		PantsLength:  m.PantsLength,
+		KneeWidth:    m.KneeWidth,
+		HemWidth:     m.HemWidth,
+		SleeveLength: m.SleeveLength,
+		CuffSize:     m.CuffSize,
+	}
+}
+
+func mapFieldToResponse(f MeasurementField, unit string) MeasurementFieldResponse {
+	if utils.IsLengthUnit(f.Unit) {
+		f.Unit = unit
+	}
+
+	return MeasurementFieldResponse{
+		ID:           f.ID.String(),
+		Name:         f.Name,
+		Unit:         f.Unit,
+		Position:     f.Position,
+		GarmentGroup: f.GarmentGroup,
+	}
+}
+
+func mapCustomValueToResponse(field MeasurementField, value float64, unit string) CustomMeasurementResponse {
+	if utils.IsLengthUnit(field.Unit) {
+		field.Unit = unit
+		value = utils.FromCentimeters(value, unit)
+	}
+
+	return CustomMeasurementResponse{
+		FieldID:      field.ID.String(),
+		Name:         field.Name,
+		Unit:         field.Unit,
+		Position:     field.Position,
+		GarmentGroup: field.GarmentGroup,
+		Value:        value,
+	}
+}
+
+func sortCustomMeasurements(values []CustomMeasurementResponse) {
+	sort.SliceStable(values, func(i, j int) bool {
+		if values[i].Position != values[j].Position {
+			return values[i].Position < values[j].Position
+		}
+		return values[i].Name < values[j].Name
+	})
+}
+
+func mapSnapshotToResponse(m MeasurementSnapshot, fields map[string]MeasurementField, unit string) MeasurementSnapshotResponse {
+	custom := []CustomMeasurementResponse{}
+	for fieldID, value := range m.CustomValues {
+		// Values of deleted fields are kept in the snapshot but not shown
+		if field, ok := fields[fieldID]; ok {
+			custom = append(custom, mapCustomValueToResponse(field, value, unit))
+		}
+	}
+	sortCustomMeasurements(custom)
+
+	return MeasurementSnapshotResponse{
+		ID:                   m.ID.String(),
+		CustomerID:           m.CustomerID.String(),
+		MeasurementUnit:      unit,
+		MeasurementsResponse: mapMeasurementsToResponse(fromCentimeters(m.Measurements, unit)),
+		CustomMeasurements:   custom,
+		CreatedAt:            m.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+func mapToResponse(c Customer, unit string) CustomerResponse {
+	custom := []CustomMeasurementResponse{}
+	for _, v := range c.CustomValues {
+		if v.Field != nil {
+			custom = append(custom, mapCustomValueToResponse(*v.Field, v.Value, unit))
+		}
+	}
+	sortCustomMeasurements(custom)
+
 	return CustomerResponse{
-		ID:               c.ID.String(),
-		UserID:           fmt.Sprintf("%d", c.UserID),
-		AvatarURL:        c.AvatarURL,
-		Name:             c.Name,
-		Phone:            c.Phone,
-		Email:            c.Email,
-		UsesStandardSize: c.UsesStandardSize,
-		StandardSize:     c.StandardSize,
-		Back:             c.Back,
-		Neck:             c.Neck,
-		FrontSize:        c.FrontSize,
-		Armhole:          c.Armhole,
-		BackSize:         c.BackSize,
-		BustChest:        c.BustChest,
-		Waist:            c.Waist,
-		Hip:              c.Hip,
-		RiseHeight:       c.RiseHeight,
-		SkirtLength:      c.SkirtLength,
-		PantsLength:      c.PantsLength,
-		KneeWidth:        c.KneeWidth,
-		HemWidth:         c.HemWidth,
-		SleeveLength:     c.SleeveLength,
-		CuffSize:         c.CuffSize,
-		CreatedAt:        c.CreatedAt.Format("2006-01-02 15:04:05"),
-		UpdatedAt:        c.UpdatedAt.Format("2006-01-02 15:04:05"),
+		ID:                   c.ID.String(),
+		UserID:               fmt.Sprintf("%d", c.UserID),
+		AvatarURL:            c.AvatarURL,
+		Name:                 c.Name,
+		Phone:                c.Phone,
+		Email:                c.Email,
+		UsesStandardSize:     c.UsesStandardSize,
+		StandardSize:         c.StandardSize,
+		MeasurementUnit:      unit,
+		MeasurementsResponse: mapMeasurementsToResponse(fromCentimeters(c.Measurements, unit)),
+		CustomMeasurements:   custom,
+		CreatedAt:            c.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:            c.UpdatedAt.Format("2006-01-02 15:04:05"),
 	}
 }
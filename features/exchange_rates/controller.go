@@ -0,0 +1,66 @@
+package exchange_rates
+
+import (
+	"errors"
+
+	"github.com/TFX0019/api-go-gds/pkg/utils"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Controller struct {
+	service  Service
+	validate *validator.Validate
+}
+
+func NewController(service Service) *Controller {
+	return &Controller{
+		service:  service,
+		validate: validator.New(),
+	}
+}
+
+func (c *Controller) GetAll(ctx *fiber.Ctx) error {
+	res, err := c.service.GetAll()
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusInternalServerError, err.Error())
+	}
+	return utils.SendSuccess(ctx, res, "exchange rates retrieved successfully")
+}
+
+func (c *Controller) Set(ctx *fiber.Ctx) error {
+	currency := ctx.Params("currency")
+	if currency == "" {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "currency required")
+	}
+
+	var req SetExchangeRateRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "invalid request body")
+	}
+
+	if err := c.validate.Struct(req); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, utils.ParseValidationError(err))
+	}
+
+	res, err := c.service.Set(currency, req)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, err.Error())
+	}
+	return utils.SendSuccess(ctx, res, "exchange rate saved successfully")
+}
+
+func (c *Controller) Delete(ctx *fiber.Ctx) error {
+	currency := ctx.Params("currency")
+	if currency == "" {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "currency required")
+	}
+
+	if err := c.service.Delete(currency); err != nil {
+		if errors.Is(err, ErrRateNotFound) {
+			return utils.SendError(ctx, fiber.StatusNotFound, err.Error())
+		}
+		return utils.SendError(ctx, fiber.StatusInternalServerError, err.Error())
+	}
+	return utils.SendSuccess(ctx, nil, "exchange rate deleted successfully")
+}
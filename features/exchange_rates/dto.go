@@ -0,0 +1,11 @@
+package exchange_rates
+
+type SetExchangeRateRequest struct {
+	RatePerUSD float64 `json:"rate_per_usd" validate:"required,gt=0"`
+}
+
+type ExchangeRateResponse struct {
+	Currency   string  `json:"currency"`
+	RatePerUSD float64 `json:"rate_per_usd"`
+	UpdatedAt  string  `json:"updated_at"`
+}
@@ -0,0 +1,17 @@
+package exchange_rates
+
+import "time"
+
+// ExchangeRate is how many units of a currency one US dollar buys. Amounts
+// are converted between two currencies through the dollar.
+type ExchangeRate struct {
+	ID         uint      `gorm:"primarykey" json:"id"`
+	Currency   string    `gorm:"type:varchar(3);uniqueIndex;not null" json:"currency"`
+	RatePerUSD float64   `gorm:"type:numeric;not null" json:"rate_per_usd"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+func (ExchangeRate) TableName() string {
+	return "exchange_rates"
+}
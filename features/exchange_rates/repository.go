@@ -0,0 +1,55 @@
+package exchange_rates
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Repository interface {
+	FindAll() ([]ExchangeRate, error)
+	Upsert(rate *ExchangeRate) error
+	Delete(currency string) (int64, error)
+	Rates() (map[string]float64, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) FindAll() ([]ExchangeRate, error) {
+	var rates []ExchangeRate
+	if err := r.db.Order("currency asc").Find(&rates).Error; err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
+
+func (r *repository) Upsert(rate *ExchangeRate) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "currency"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rate_per_usd", "updated_at"}),
+	}).Create(rate).Error
+}
+
+func (r *repository) Delete(currency string) (int64, error) {
+	res := r.db.Where("currency = ?", currency).Delete(&ExchangeRate{})
+	return res.RowsAffected, res.Error
+}
+
+// Rates returns the rate of every currency, keyed by code.
+func (r *repository) Rates() (map[string]float64, error) {
+	rates, err := r.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[string]float64, len(rates))
+	for _, rate := range rates {
+		res[rate.Currency] = rate.RatePerUSD
+	}
+	return res, nil
+}
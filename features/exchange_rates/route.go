@@ -0,0 +1,18 @@
+package exchange_rates
+
+import (
+	"github.com/TFX0019/api-go-gds/pkg/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+func RegisterRoutes(app fiber.Router, controller *Controller) {
+	route := app.Group("/api/exchange-rates", middleware.Protected())
+
+	// accessible for everyone authenticated
+	route.Get("/", controller.GetAll)
+
+	// Admin only
+	adminRoute := route.Group("/", middleware.RequireRole("admin"))
+	adminRoute.Put("/:currency", controller.Set)
+	adminRoute.Delete("/:currency", controller.Delete)
+}
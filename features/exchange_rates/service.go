@@ -0,0 +1,112 @@
+package exchange_rates
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/TFX0019/api-go-gds/pkg/utils"
+)
+
+var (
+	ErrRateNotFound = errors.New("exchange rate not found")
+	ErrMissingRate  = errors.New("missing exchange rate")
+)
+
+type Service interface {
+	GetAll() ([]ExchangeRateResponse, error)
+	Set(currency string, req SetExchangeRateRequest) (*ExchangeRateResponse, error)
+	Delete(currency string) error
+}
+
+type service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) Service {
+	return &service{repo: repo}
+}
+
+func (s *service) GetAll() ([]ExchangeRateResponse, error) {
+	rates, err := s.repo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]ExchangeRateResponse, 0, len(rates))
+	for _, r := range rates {
+		res = append(res, mapToResponse(r))
+	}
+	return res, nil
+}
+
+func (s *service) Set(currency string, req SetExchangeRateRequest) (*ExchangeRateResponse, error) {
+	currency = strings.ToUpper(currency)
+	if !utils.IsCurrency(currency) {
+		return nil, fmt.Errorf("unsupported currency %s", currency)
+	}
+	if currency == utils.DefaultCurrency {
+		return nil, errors.New("the US dollar is the base currency and always has a rate of 1")
+	}
+
+	rate := &ExchangeRate{
+		Currency:   currency,
+		RatePerUSD: req.RatePerUSD,
+		UpdatedAt:  time.Now(),
+	}
+	if err := s.repo.Upsert(rate); err != nil {
+		return nil, err
+	}
+
+	res := mapToResponse(*rate)
+	return &res, nil
+}
+
+func (s *service) Delete(currency string) error {
+	deleted, err := s.repo.Delete(strings.ToUpper(currency))
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return ErrRateNotFound
+	}
+	return nil
+}
+
+// Convert changes amount from one currency to another with rates as
+// returned by Repository.Rates.
+func Convert(rates map[string]float64, amount utils.Money, from, to string) (utils.Money, error) {
+	if from == to {
+		return amount, nil
+	}
+
+	fromRate, err := rateOf(rates, from)
+	if err != nil {
+		return utils.Zero, err
+	}
+	toRate, err := rateOf(rates, to)
+	if err != nil {
+		return utils.Zero, err
+	}
+	return amount.Mul(toRate).Div(fromRate), nil
+}
+
+func rateOf(rates map[string]float64, currency string) (float64, error) {
+	if currency == utils.DefaultCurrency {
+		return 1, nil
+	}
+	rate, ok := rates[currency]
+	if !ok || rate <= 0 {
+		return 0, fmt.Errorf("%w for %s", ErrMissingRate, currency)
+	}
+	return rate, nil
+}
+
+func mapToResponse(r ExchangeRate) ExchangeRateResponse {
+	return ExchangeRateResponse{
+		Currency:   r.Currency,
+		RatePerUSD: r.RatePerUSD,
+		UpdatedAt:  r.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
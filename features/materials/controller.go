@@ -1,6 +1,7 @@
 package materials
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"path/filepath"
@@ -20,7 +21,7 @@ type Controller struct {
 func NewController(service Service) *Controller {
 	return &Controller{
 		service:  service,
-		validate: validator.New(),
+		validate: utils.NewValidator(),
 	}
 }
 
@@ -130,6 +131,10 @@ func (c *Controller) Update(ctx *fiber.Ctx) error {
 		return utils.SendError(ctx, fiber.StatusBadRequest, "invalid request body")
 	}
 
+	if err := c.validate.Struct(req); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, utils.ParseValidationError(err))
+	}
+
 	var imageURL string
 	file, err := ctx.FormFile("image")
 	if err == nil {
@@ -162,6 +167,143 @@ func (c *Controller) Delete(ctx *fiber.Ctx) error {
 	return utils.SendSuccess(ctx, nil, "material deleted successfully")
 }
 
+func (c *Controller) CreateMovement(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	id := ctx.Params("id")
+	if id == "" {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "id required")
+	}
+
+	var req CreateMovementRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "invalid request body")
+	}
+
+	if err := c.validate.Struct(req); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, utils.ParseValidationError(err))
+	}
+
+	res, err := c.service.CreateMovement(userID, id, req)
+	if err != nil {
+		if errors.Is(err, ErrMaterialNotFound) {
+			return utils.SendError(ctx, fiber.StatusNotFound, err.Error())
+		}
+		return utils.SendError(ctx, fiber.StatusBadRequest, err.Error())
+	}
+
+	return utils.SendCreated(ctx, res, "stock movement recorded successfully")
+}
+
+func (c *Controller) GetMovements(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	id := ctx.Params("id")
+	if id == "" {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "id required")
+	}
+
+	var query MovementQuery
+	if err := ctx.QueryParser(&query); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "invalid query parameters")
+	}
+
+	if err := c.validate.Struct(query); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, utils.ParseValidationError(err))
+	}
+
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.Limit < 1 {
+		query.Limit = 10
+	}
+
+	res, err := c.service.GetMovements(userID, id, query)
+	if err != nil {
+		if errors.Is(err, ErrMaterialNotFound) {
+			return utils.SendError(ctx, fiber.StatusNotFound, err.Error())
+		}
+		return utils.SendError(ctx, fiber.StatusBadRequest, err.Error())
+	}
+
+	return utils.SendSuccess(ctx, res, "stock movements retrieved successfully")
+}
+
+func (c *Controller) GetStock(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	var query StockQuery
+	if err := ctx.QueryParser(&query); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "invalid query parameters")
+	}
+
+	if err := c.validate.Struct(query); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, utils.ParseValidationError(err))
+	}
+
+	res, err := c.service.GetStock(userID, query)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(ctx, res, "stock retrieved successfully")
+}
+
+func (c *Controller) GetLowStock(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	res, err := c.service.GetLowStock(userID)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(ctx, res, "low stock materials retrieved successfully")
+}
+
+func (c *Controller) GetMaterialStock(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	id := ctx.Params("id")
+	if id == "" {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "id required")
+	}
+
+	var query StockQuery
+	if err := ctx.QueryParser(&query); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "invalid query parameters")
+	}
+
+	if err := c.validate.Struct(query); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, utils.ParseValidationError(err))
+	}
+
+	res, err := c.service.GetMaterialStock(userID, id, query)
+	if err != nil {
+		if errors.Is(err, ErrMaterialNotFound) {
+			return utils.SendError(ctx, fiber.StatusNotFound, err.Error())
+		}
+		return utils.SendError(ctx, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(ctx, res, "stock retrieved successfully")
+}
+
 func getUserIDFromToken(ctx *fiber.Ctx) (string, error) {
 	userToken := ctx.Locals("user")
 	if userToken == nil {
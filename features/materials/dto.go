@@ -1,17 +1,26 @@
 package materials
 
+import "github.com/TFX0019/api-go-gds/pkg/utils"
+
 type CreateMaterialRequest struct {
-	Name     string  `form:"name" validate:"required"`
-	Price    float64 `form:"price" validate:"required,gte=0"`
-	Quantity float64 `form:"quantity" validate:"gte=0"`
-	Unit     string  `form:"unit" validate:"required"`
+	Name     string      `form:"name" validate:"required"`
+	Price    utils.Money `form:"price" validate:"required,gte=0"`
+	Currency string      `form:"currency" validate:"omitempty,len=3"` // Defaults to the user's currency
+	Quantity float64     `form:"quantity" validate:"gte=0"`
+	Unit     string      `form:"unit" validate:"required"`
+	// 0 turns the low-stock alert off
+	ReorderThreshold float64 `form:"reorder_threshold" validate:"gte=0"`
 }
 
 type UpdateMaterialRequest struct {
-	Name     string  `form:"name"`
-	Price    float64 `form:"price" validate:"gte=0"`
-	Quantity float64 `form:"quantity" validate:"gte=0"`
-	Unit     string  `form:"unit"`
+	Name     string      `form:"name"`
+	Price    utils.Money `form:"price" validate:"gte=0"`
+	Currency string      `form:"currency" validate:"omitempty,len=3"`
+	// Kept when not sent; a new one is recorded as a stock adjustment
+	Quantity *float64 `form:"quantity" validate:"omitempty,gte=0"`
+	Unit     string   `form:"unit"`
+	// Kept when not sent; 0 turns the low-stock alert off
+	ReorderThreshold *float64 `form:"reorder_threshold" validate:"omitempty,gte=0"`
 }
 
 type PaginationQuery struct {
@@ -20,15 +29,26 @@ type PaginationQuery struct {
 }
 
 type MaterialResponse struct {
-	ID        string  `json:"id"`
-	UserID    string  `json:"user_id"`
-	Name      string  `json:"name"`
-	Price     float64 `json:"price"`
-	Quantity  float64 `json:"quantity"`
-	Unit      string  `json:"unit"`
-	ImageURL  string  `json:"image_url"`
-	CreatedAt string  `json:"created_at"`
-	UpdatedAt string  `json:"updated_at"`
+	ID       string      `json:"id"`
+	UserID   string      `json:"user_id"`
+	Name     string      `json:"name"`
+	Price    utils.Money `json:"price"`
+	Currency string      `json:"currency"`
+	Quantity float64     `json:"quantity"`
+	Unit     string      `json:"unit"`
+	ImageURL string      `json:"image_url"`
+	// 0 when the low-stock alert is off
+	ReorderThreshold float64 `json:"reorder_threshold"`
+	CreatedAt        string  `json:"created_at"`
+	UpdatedAt        string  `json:"updated_at"`
+}
+
+// LowStockResponse is a material whose stock, less what pending orders
+// have reserved, is at or below its reorder threshold.
+type LowStockResponse struct {
+	MaterialResponse
+	Reserved  float64 `json:"reserved"`
+	Available float64 `json:"available"`
 }
 
 type PaginatedResponse struct {
@@ -37,3 +57,51 @@ type PaginatedResponse struct {
 	Page  int                `json:"page"`
 	Limit int                `json:"limit"`
 }
+
+type CreateMovementRequest struct {
+	Type string `json:"type" validate:"required,oneof=purchase adjustment waste"`
+	// Positive for purchases and waste; adjustments are signed
+	Quantity   float64 `json:"quantity" validate:"required"`
+	OccurredAt *string `json:"occurred_at" validate:"omitempty,datetime=2006-01-02"`
+	Note       string  `json:"note" validate:"max=255"`
+}
+
+type MovementQuery struct {
+	Page  int    `query:"page"`
+	Limit int    `query:"limit" validate:"omitempty,max=100"`
+	From  string `query:"from" validate:"omitempty,datetime=2006-01-02"`
+	To    string `query:"to" validate:"omitempty,datetime=2006-01-02"`
+	Type  string `query:"type" validate:"omitempty,oneof=purchase consumption adjustment waste"`
+}
+
+type MovementResponse struct {
+	ID         string  `json:"id"`
+	MaterialID string  `json:"material_id"`
+	Type       string  `json:"type"`
+	Quantity   float64 `json:"quantity"`
+	ProductID  *string `json:"product_id"`
+	Note       string  `json:"note"`
+	OccurredAt string  `json:"occurred_at"`
+	CreatedAt  string  `json:"created_at"`
+}
+
+type MovementsResponse struct {
+	Data  []MovementResponse `json:"data"`
+	Total int64              `json:"total"`
+	Page  int                `json:"page"`
+	Limit int                `json:"limit"`
+}
+
+// StockQuery selects the day whose closing stock is reported; it defaults
+// to today.
+type StockQuery struct {
+	Date string `query:"date" validate:"omitempty,datetime=2006-01-02"`
+}
+
+type StockResponse struct {
+	MaterialID string  `json:"material_id"`
+	Name       string  `json:"name"`
+	Unit       string  `json:"unit"`
+	Quantity   float64 `json:"quantity"`
+	Date       string  `json:"date"`
+}
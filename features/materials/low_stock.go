@@ -0,0 +1,20 @@
+package materials
+
+// GetLowStock lists the user's materials that need reordering once the
+// quantities reserved by pending orders are set aside.
+func (s *service) GetLowStock(userID string) ([]LowStockResponse, error) {
+	rows, err := s.repo.FindLowStock(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]LowStockResponse, 0, len(rows))
+	for _, row := range rows {
+		res = append(res, LowStockResponse{
+			MaterialResponse: mapToResponse(row.Material),
+			Reserved:         row.Reserved,
+			Available:        row.Quantity - row.Reserved,
+		})
+	}
+	return res, nil
+}
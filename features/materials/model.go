@@ -3,21 +3,49 @@ package materials
 import (
 	"time"
 
+	"github.com/TFX0019/api-go-gds/pkg/utils"
 	"github.com/google/uuid"
 )
 
 type Material struct {
-	ID        uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
-	UserID    uint      `gorm:"not null"`
-	Name      string    `gorm:"type:text;not null"`
-	Price     float64   `gorm:"type:numeric;not null"`
-	Quantity  float64   `gorm:"type:numeric;default:0"`
-	Unit      string    `gorm:"type:text;not null"`
-	ImageURL  string    `gorm:"type:text"`
-	CreatedAt time.Time `gorm:"not null;default:now()"`
-	UpdatedAt time.Time `gorm:"not null;default:now()"`
+	ID       uuid.UUID   `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	UserID   uint        `gorm:"not null"`
+	Name     string      `gorm:"type:text;not null"`
+	Price    utils.Money `gorm:"type:numeric;not null"`
+	Currency string      `gorm:"type:varchar(3);not null;default:'USD'"`
+	Quantity float64     `gorm:"type:numeric;default:0"`
+	Unit     string      `gorm:"type:text;not null"`
+	ImageURL string      `gorm:"type:text"`
+	// Stock at which the owner is told to reorder; 0 turns the alert off
+	ReorderThreshold float64 `gorm:"type:numeric;not null;default:0"`
+	// Set when the owner is alerted and cleared once the stock is back above
+	// the threshold, so each crossing is notified once
+	LowStockNotifiedAt *time.Time
+	CreatedAt          time.Time `gorm:"not null;default:now()"`
+	UpdatedAt          time.Time `gorm:"not null;default:now()"`
 }
 
 func (Material) TableName() string {
 	return "materials"
 }
+
+// StockMovement is an entry in a material's stock ledger. Its quantity is
+// signed: purchases add to the stock, consumption and waste take from it and
+// adjustments go either way. The material's quantity is the sum of its
+// movements.
+type StockMovement struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	MaterialID uuid.UUID  `gorm:"type:uuid;not null;index"`
+	Material   *Material  `gorm:"foreignKey:MaterialID;constraint:OnDelete:CASCADE;"`
+	UserID     uint       `gorm:"not null;index"`
+	Type       string     `gorm:"type:text;not null"`
+	Quantity   float64    `gorm:"type:numeric;not null"`
+	ProductID  *uuid.UUID `gorm:"type:uuid;index"` // Set on consumption by an order
+	Note       string     `gorm:"type:text"`
+	OccurredAt time.Time  `gorm:"not null;index"`
+	CreatedAt  time.Time  `gorm:"not null;default:now()"`
+}
+
+func (StockMovement) TableName() string {
+	return "material_stock_movements"
+}
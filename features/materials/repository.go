@@ -1,14 +1,47 @@
 package materials
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type Repository interface {
 	Create(material *Material) error
 	FindAll(limit, offset int) ([]Material, int64, error)
 	FindByID(id string) (*Material, error)
 	FindByUserID(userID string, limit, offset int) ([]Material, int64, error)
-	Update(material *Material) error
+	Update(material *Material, movement *StockMovement) error
 	Delete(id string) error
+	CreateMovement(movement *StockMovement) error
+	FindMovements(materialID string, from, to *time.Time, movementType string, limit, offset int) ([]StockMovement, int64, error)
+	FindStockAsOf(userID, materialID string, at time.Time) ([]StockRow, error)
+	FindLowStock(userID string) ([]LowStockRow, error)
+}
+
+// ReservedQuantity is the quantity of a material held by pending orders
+// that have not taken it out of stock yet.
+const ReservedQuantity = `COALESCE((SELECT SUM(pm.quantity) FROM product_materials pm
+	JOIN products p ON p.id = pm.product_id
+	WHERE pm.material_id = materials.id AND p.status = 'pending' AND NOT p.stock_consumed), 0)`
+
+// LowStockCondition matches the materials whose stock, less what is
+// reserved, is at or below their reorder threshold.
+const LowStockCondition = `(materials.reorder_threshold > 0 AND
+	materials.quantity - ` + ReservedQuantity + ` <= materials.reorder_threshold)`
+
+// LowStockRow is a low-stock material with the quantity reserved for it.
+type LowStockRow struct {
+	Material `gorm:"embedded"`
+	Reserved float64
+}
+
+// StockRow is a material's stock at a point in time.
+type StockRow struct {
+	MaterialID string
+	Name       string
+	Unit       string
+	Quantity   float64
 }
 
 type repository struct {
@@ -19,8 +52,25 @@ func NewRepository(db *gorm.DB) Repository {
 	return &repository{db: db}
 }
 
+// Create saves the material and records its initial quantity as the
+// opening balance of its stock ledger.
 func (r *repository) Create(material *Material) error {
-	return r.db.Create(material).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(material).Error; err != nil {
+			return err
+		}
+		if material.Quantity == 0 {
+			return nil
+		}
+		return tx.Create(&StockMovement{
+			MaterialID: material.ID,
+			UserID:     material.UserID,
+			Type:       MovementAdjustment,
+			Quantity:   material.Quantity,
+			Note:       openingBalanceNote,
+			OccurredAt: material.CreatedAt,
+		}).Error
+	})
 }
 
 func (r *repository) FindAll(limit, offset int) ([]Material, int64, error) {
@@ -66,10 +116,96 @@ func (r *repository) FindByUserID(userID string, limit, offset int) ([]Material,
 	return materials, total, nil
 }
 
-func (r *repository) Update(material *Material) error {
-	return r.db.Save(material).Error
+// Update saves the material and, when not nil, records the movement that
+// changes its quantity. The quantity itself only moves through the ledger.
+func (r *repository) Update(material *Material, movement *StockMovement) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Quantity").Save(material).Error; err != nil {
+			return err
+		}
+		if movement == nil {
+			return nil
+		}
+		return RecordMovement(tx, movement)
+	})
 }
 
 func (r *repository) Delete(id string) error {
 	return r.db.Delete(&Material{}, "id = ?", id).Error
 }
+
+func (r *repository) CreateMovement(movement *StockMovement) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return RecordMovement(tx, movement)
+	})
+}
+
+// RecordMovement adds the movement to the ledger and moves the material's
+// quantity by it. It runs inside the caller's transaction so other features
+// can move stock along with their own changes.
+func RecordMovement(tx *gorm.DB, movement *StockMovement) error {
+	if err := tx.Omit("Material").Create(movement).Error; err != nil {
+		return err
+	}
+	return tx.Model(&Material{}).Where("id = ?", movement.MaterialID).
+		Update("quantity", gorm.Expr("quantity + ?", movement.Quantity)).Error
+}
+
+func (r *repository) FindMovements(materialID string, from, to *time.Time, movementType string, limit, offset int) ([]StockMovement, int64, error) {
+	var movements []StockMovement
+	var total int64
+
+	query := r.db.Model(&StockMovement{}).Where("material_id = ?", materialID)
+	if from != nil {
+		query = query.Where("occurred_at >= ?", *from)
+	}
+	if to != nil {
+		query = query.Where("occurred_at < ?", *to)
+	}
+	if movementType != "" {
+		query = query.Where("type = ?", movementType)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Limit(limit).Offset(offset).Order("occurred_at desc, created_at desc").Find(&movements).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return movements, total, nil
+}
+
+// FindStockAsOf sums the movements before at for the user's materials, or
+// for a single one when materialID is not empty.
+func (r *repository) FindStockAsOf(userID, materialID string, at time.Time) ([]StockRow, error) {
+	var rows []StockRow
+	query := r.db.Table("materials m").
+		Select("m.id AS material_id, m.name, m.unit, COALESCE(SUM(sm.quantity), 0) AS quantity").
+		Joins("LEFT JOIN material_stock_movements sm ON sm.material_id = m.id AND sm.occurred_at < ?", at).
+		Where("m.user_id = ?", userID)
+	if materialID != "" {
+		query = query.Where("m.id = ?", materialID)
+	}
+	err := query.Group("m.id, m.name, m.unit").Order("m.name asc").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (r *repository) FindLowStock(userID string) ([]LowStockRow, error) {
+	var rows []LowStockRow
+	err := r.db.Model(&Material{}).
+		Select("materials.*, "+ReservedQuantity+" AS reserved").
+		Where("materials.user_id = ?", userID).
+		Where(LowStockCondition).
+		Order("materials.name asc").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
@@ -11,7 +11,12 @@ func RegisterRoutes(app fiber.Router, controller *Controller) {
 	route.Post("/", controller.Create)
 	route.Get("/", controller.GetAll)
 	route.Get("/user", controller.GetByUserID)
+	route.Get("/stock", controller.GetStock)
+	route.Get("/low-stock", controller.GetLowStock)
 	route.Get("/:id", controller.GetByID)
 	route.Put("/:id", controller.Update)
 	route.Delete("/:id", controller.Delete)
+	route.Get("/:id/stock", controller.GetMaterialStock)
+	route.Get("/:id/movements", controller.GetMovements)
+	route.Post("/:id/movements", controller.CreateMovement)
 }
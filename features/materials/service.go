@@ -4,6 +4,11 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TFX0019/api-go-gds/features/auth"
+	"github.com/TFX0019/api-go-gds/pkg/utils"
 )
 
 type Service interface {
@@ -13,14 +18,20 @@ type Service interface {
 	GetByUserID(userID string, page, limit int) (*PaginatedResponse, error)
 	Update(id string, req UpdateMaterialRequest, imageURL string) (*MaterialResponse, error)
 	Delete(id string) error
+	CreateMovement(userID, materialID string, req CreateMovementRequest) (*MovementResponse, error)
+	GetMovements(userID, materialID string, query MovementQuery) (*MovementsResponse, error)
+	GetStock(userID string, query StockQuery) ([]StockResponse, error)
+	GetMaterialStock(userID, materialID string, query StockQuery) (*StockResponse, error)
+	GetLowStock(userID string) ([]LowStockResponse, error)
 }
 
 type service struct {
-	repo Repository
+	repo     Repository
+	authRepo auth.Repository
 }
 
-func NewService(repo Repository) Service {
-	return &service{repo: repo}
+func NewService(repo Repository, authRepo auth.Repository) Service {
+	return &service{repo: repo, authRepo: authRepo}
 }
 
 func (s *service) Create(userID string, req CreateMaterialRequest, imageURL string) (*MaterialResponse, error) {
@@ -29,13 +40,26 @@ func (s *service) Create(userID string, req CreateMaterialRequest, imageURL stri
 		return nil, errors.New("invalid user id")
 	}
 
+	currency := strings.ToUpper(req.Currency)
+	if currency == "" {
+		currency = utils.DefaultCurrency
+		if user, err := s.authRepo.FindByID(uint(uid)); err == nil && user.Currency != "" {
+			currency = user.Currency
+		}
+	}
+	if !utils.IsCurrency(currency) {
+		return nil, errors.New("unsupported currency")
+	}
+
 	material := &Material{
-		UserID:   uint(uid),
-		Name:     req.Name,
-		Price:    req.Price,
-		Quantity: req.Quantity,
-		Unit:     req.Unit,
-		ImageURL: imageURL,
+		UserID:           uint(uid),
+		Name:             req.Name,
+		Price:            req.Price,
+		Currency:         currency,
+		Quantity:         req.Quantity,
+		Unit:             req.Unit,
+		ImageURL:         imageURL,
+		ReorderThreshold: req.ReorderThreshold,
 	}
 
 	if err := s.repo.Create(material); err != nil {
@@ -122,17 +146,45 @@ func (s *service) Update(id string, req UpdateMaterialRequest, imageURL string)
 	if req.Unit != "" {
 		material.Unit = req.Unit
 	}
+	if req.Currency != "" {
+		currency := strings.ToUpper(req.Currency)
+		if !utils.IsCurrency(currency) {
+			return nil, errors.New("unsupported currency")
+		}
+		material.Currency = currency
+	}
 	// Price is tricky if 0 is valid. I'll update it for now.
 	material.Price = req.Price
-	material.Quantity = req.Quantity
+	if req.ReorderThreshold != nil && *req.ReorderThreshold != material.ReorderThreshold {
+		// A new threshold is a new crossing to alert about
+		material.ReorderThreshold = *req.ReorderThreshold
+		material.LowStockNotifiedAt = nil
+	}
+
+	// A counted quantity is recorded as an adjustment so the ledger keeps
+	// adding up; without one the stock only moves through movements
+	var movement *StockMovement
+	if req.Quantity != nil && *req.Quantity != material.Quantity {
+		movement = &StockMovement{
+			MaterialID: material.ID,
+			UserID:     material.UserID,
+			Type:       MovementAdjustment,
+			Quantity:   *req.Quantity - material.Quantity,
+			Note:       "Quantity updated",
+			OccurredAt: time.Now(),
+		}
+	}
 
 	if imageURL != "" {
 		material.ImageURL = imageURL
 	}
 
-	if err := s.repo.Update(material); err != nil {
+	if err := s.repo.Update(material, movement); err != nil {
 		return nil, err
 	}
+	if movement != nil {
+		material.Quantity += movement.Quantity
+	}
 
 	res := mapToResponse(*material)
 	return &res, nil
@@ -144,14 +196,16 @@ func (s *service) Delete(id string) error {
 
 func mapToResponse(m Material) MaterialResponse {
 	return MaterialResponse{
-		ID:        m.ID.String(),
-		UserID:    fmt.Sprintf("%d", m.UserID),
-		Name:      m.Name,
-		Price:     m.Price,
-		Quantity:  m.Quantity,
-		Unit:      m.Unit,
-		ImageURL:  m.ImageURL,
-		CreatedAt: m.CreatedAt.Format("2006-01-02 15:04:05"),
-		UpdatedAt: m.UpdatedAt.Format("2006-01-02 15:04:05"),
+		ID:               m.ID.String(),
+		UserID:           fmt.Sprintf("%d", m.UserID),
+		Name:             m.Name,
+		Price:            m.Price,
+		Currency:         m.Currency,
+		Quantity:         m.Quantity,
+		Unit:             m.Unit,
+		ImageURL:         m.ImageURL,
+		ReorderThreshold: m.ReorderThreshold,
+		CreatedAt:        m.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:        m.UpdatedAt.Format("2006-01-02 15:04:05"),
 	}
 }
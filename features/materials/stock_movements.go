@@ -0,0 +1,190 @@
+package materials
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrMaterialNotFound = errors.New("material not found")
+	ErrInvalidQuantity  = errors.New("purchases and waste take a positive quantity")
+)
+
+const (
+	MovementPurchase    = "purchase"
+	MovementConsumption = "consumption"
+	MovementAdjustment  = "adjustment"
+	MovementWaste       = "waste"
+)
+
+const openingBalanceNote = "Opening balance"
+
+// findOwned returns the material when it belongs to the user.
+func (s *service) findOwned(userID, id string) (*Material, error) {
+	material, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, ErrMaterialNotFound
+	}
+	if fmt.Sprintf("%d", material.UserID) != userID {
+		return nil, ErrMaterialNotFound
+	}
+	return material, nil
+}
+
+// CreateMovement records a purchase, waste or manual adjustment of the
+// material's stock. Consumption is recorded by the orders that use it.
+func (s *service) CreateMovement(userID, materialID string, req CreateMovementRequest) (*MovementResponse, error) {
+	material, err := s.findOwned(userID, materialID)
+	if err != nil {
+		return nil, err
+	}
+
+	quantity := req.Quantity
+	switch req.Type {
+	case MovementPurchase:
+		if quantity < 0 {
+			return nil, ErrInvalidQuantity
+		}
+	case MovementWaste:
+		if quantity < 0 {
+			return nil, ErrInvalidQuantity
+		}
+		quantity = -quantity
+	}
+
+	occurredAt := time.Now()
+	if req.OccurredAt != nil && *req.OccurredAt != "" {
+		date, err := time.Parse("2006-01-02", *req.OccurredAt)
+		if err != nil {
+			return nil, errors.New("invalid date format, use YYYY-MM-DD")
+		}
+		occurredAt = date
+	}
+
+	movement := &StockMovement{
+		MaterialID: material.ID,
+		UserID:     material.UserID,
+		Type:       req.Type,
+		Quantity:   quantity,
+		Note:       req.Note,
+		OccurredAt: occurredAt,
+	}
+	if err := s.repo.CreateMovement(movement); err != nil {
+		return nil, err
+	}
+
+	res := mapMovementToResponse(*movement)
+	return &res, nil
+}
+
+func (s *service) GetMovements(userID, materialID string, query MovementQuery) (*MovementsResponse, error) {
+	material, err := s.findOwned(userID, materialID)
+	if err != nil {
+		return nil, err
+	}
+
+	var from, to *time.Time
+	if query.From != "" {
+		date, err := time.Parse("2006-01-02", query.From)
+		if err != nil {
+			return nil, errors.New("invalid from date, use YYYY-MM-DD")
+		}
+		from = &date
+	}
+	if query.To != "" {
+		date, err := time.Parse("2006-01-02", query.To)
+		if err != nil {
+			return nil, errors.New("invalid to date, use YYYY-MM-DD")
+		}
+		// The to date is inclusive
+		date = date.AddDate(0, 0, 1)
+		to = &date
+	}
+
+	offset := (query.Page - 1) * query.Limit
+	movements, total, err := s.repo.FindMovements(material.ID.String(), from, to, query.Type, query.Limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]MovementResponse, 0, len(movements))
+	for _, m := range movements {
+		responses = append(responses, mapMovementToResponse(m))
+	}
+
+	return &MovementsResponse{
+		Data:  responses,
+		Total: total,
+		Page:  query.Page,
+		Limit: query.Limit,
+	}, nil
+}
+
+// GetStock returns the stock of the user's materials at the end of the
+// given day, summed from their movements.
+func (s *service) GetStock(userID string, query StockQuery) ([]StockResponse, error) {
+	return s.stockAsOf(userID, "", query)
+}
+
+func (s *service) GetMaterialStock(userID, materialID string, query StockQuery) (*StockResponse, error) {
+	material, err := s.findOwned(userID, materialID)
+	if err != nil {
+		return nil, err
+	}
+	stock, err := s.stockAsOf(userID, material.ID.String(), query)
+	if err != nil {
+		return nil, err
+	}
+	if len(stock) == 0 {
+		return nil, ErrMaterialNotFound
+	}
+	return &stock[0], nil
+}
+
+func (s *service) stockAsOf(userID, materialID string, query StockQuery) ([]StockResponse, error) {
+	now := time.Now()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if query.Date != "" {
+		date, err := time.Parse("2006-01-02", query.Date)
+		if err != nil {
+			return nil, errors.New("invalid date format, use YYYY-MM-DD")
+		}
+		day = date
+	}
+
+	rows, err := s.repo.FindStockAsOf(userID, materialID, day.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]StockResponse, 0, len(rows))
+	for _, row := range rows {
+		res = append(res, StockResponse{
+			MaterialID: row.MaterialID,
+			Name:       row.Name,
+			Unit:       row.Unit,
+			Quantity:   row.Quantity,
+			Date:       day.Format("2006-01-02"),
+		})
+	}
+	return res, nil
+}
+
+func mapMovementToResponse(m StockMovement) MovementResponse {
+	var productID *string
+	if m.ProductID != nil {
+		id := m.ProductID.String()
+		productID = &id
+	}
+	return MovementResponse{
+		ID:         m.ID.String(),
+		MaterialID: m.MaterialID.String(),
+		Type:       m.Type,
+		Quantity:   m.Quantity,
+		ProductID:  productID,
+		Note:       m.Note,
+		OccurredAt: m.OccurredAt.Format("2006-01-02 15:04:05"),
+		CreatedAt:  m.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
@@ -25,7 +25,7 @@ type controller struct {
 func NewController(service Service) Controller {
 	return &controller{
 		service:  service,
-		validate: validator.New(),
+		validate: utils.NewValidator(),
 	}
 }
 
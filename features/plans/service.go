@@ -1,5 +1,12 @@
 package plans
 
+import (
+	"errors"
+	"strings"
+
+	"github.com/TFX0019/api-go-gds/pkg/utils"
+)
+
 type Service interface {
 	ListAll() ([]Plan, error)
 	ListActive() ([]Plan, error)
@@ -40,6 +47,13 @@ func (s *service) Update(id uint, dto PlanUpdateDTO) (*Plan, error) {
 	if dto.Price != nil {
 		plan.Price = *dto.Price
 	}
+	if dto.Currency != nil {
+		currency := strings.ToUpper(*dto.Currency)
+		if !utils.IsCurrency(currency) {
+			return nil, errors.New("unsupported currency")
+		}
+		plan.Currency = currency
+	}
 	if dto.Benefits != nil {
 		// Ensure benefits is not nil or empty if provided
 		plan.Benefits = dto.Benefits
@@ -102,11 +116,20 @@ func (s *service) Create(dto PlanCreateDTO) (*Plan, error) {
 		isActive = *dto.IsActive
 	}
 
+	currency := utils.DefaultCurrency
+	if dto.Currency != "" {
+		currency = strings.ToUpper(dto.Currency)
+		if !utils.IsCurrency(currency) {
+			return nil, errors.New("unsupported currency")
+		}
+	}
+
 	plan := &Plan{
 		ProductID:    dto.ProductID,
 		Title:        dto.Title,
 		Description:  dto.Description,
 		Price:        dto.Price,
+		Currency:     currency,
 		Benefits:     dto.Benefits,
 		MaxCustomers: *dto.MaxCustomers,
 		MaxProducts:  *dto.MaxProducts,
@@ -0,0 +1,255 @@
+package product_templates
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/TFX0019/api-go-gds/features/products"
+	"github.com/TFX0019/api-go-gds/pkg/utils"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+type Controller struct {
+	service  Service
+	validate *validator.Validate
+}
+
+func NewController(service Service) *Controller {
+	return &Controller{
+		service:  service,
+		validate: utils.NewValidator(),
+	}
+}
+
+func (c *Controller) GetAll(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	res, err := c.service.GetAll(userID)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusInternalServerError, err.Error())
+	}
+	return utils.SendSuccess(ctx, res, "templates retrieved successfully")
+}
+
+func (c *Controller) GetByID(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	id := ctx.Params("id")
+	if id == "" {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "id required")
+	}
+
+	res, err := c.service.GetByID(userID, id)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusNotFound, err.Error())
+	}
+	return utils.SendSuccess(ctx, res, "template retrieved successfully")
+}
+
+func (c *Controller) Create(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	var req CreateTemplateRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "invalid request body")
+	}
+
+	if err := c.validate.Struct(req); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, utils.ParseValidationError(err))
+	}
+
+	res, err := c.service.Create(userID, req)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, err.Error())
+	}
+	return utils.SendCreated(ctx, res, "template created successfully")
+}
+
+func (c *Controller) Update(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	id := ctx.Params("id")
+	if id == "" {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "id required")
+	}
+
+	var req UpdateTemplateRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "invalid request body")
+	}
+
+	if err := c.validate.Struct(req); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, utils.ParseValidationError(err))
+	}
+
+	res, err := c.service.Update(userID, id, req)
+	if err != nil {
+		if errors.Is(err, ErrTemplateNotFound) {
+			return utils.SendError(ctx, fiber.StatusNotFound, err.Error())
+		}
+		return utils.SendError(ctx, fiber.StatusBadRequest, err.Error())
+	}
+	return utils.SendSuccess(ctx, res, "template updated successfully")
+}
+
+func (c *Controller) Delete(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	id := ctx.Params("id")
+	if id == "" {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "id required")
+	}
+
+	if err := c.service.Delete(userID, id); err != nil {
+		if errors.Is(err, ErrTemplateNotFound) {
+			return utils.SendError(ctx, fiber.StatusNotFound, err.Error())
+		}
+		return utils.SendError(ctx, fiber.StatusInternalServerError, err.Error())
+	}
+	return utils.SendSuccess(ctx, nil, "template deleted successfully")
+}
+
+func (c *Controller) UploadImages(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	id := ctx.Params("id")
+	if id == "" {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "id required")
+	}
+
+	form, err := ctx.MultipartForm()
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "invalid request or missing files")
+	}
+
+	files := form.File["images"]
+	if len(files) == 0 {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "no images provided")
+	}
+
+	if len(files) > maxImages {
+		return utils.SendError(ctx, fiber.StatusBadRequest, fmt.Sprintf("maximum %d images allowed per upload", maxImages))
+	}
+
+	var paths []string
+	for _, file := range files {
+		filename := fmt.Sprintf("%s-%s", uuid.New().String(), file.Filename)
+		path := filepath.Join("uploads", filename)
+		if err := ctx.SaveFile(file, path); err != nil {
+			return utils.SendError(ctx, fiber.StatusInternalServerError, "failed to save image")
+		}
+		paths = append(paths, fmt.Sprintf("uploads/%s", filename))
+	}
+
+	res, err := c.service.AddImages(userID, id, paths)
+	if err != nil {
+		removeImages(paths)
+		if errors.Is(err, ErrTemplateNotFound) {
+			return utils.SendError(ctx, fiber.StatusNotFound, err.Error())
+		}
+		return utils.SendError(ctx, fiber.StatusBadRequest, err.Error())
+	}
+	return utils.SendSuccess(ctx, res, "images uploaded successfully")
+}
+
+func (c *Controller) DeleteImage(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	id := ctx.Params("id")
+	imageID := ctx.Params("image_id")
+	if id == "" || imageID == "" {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "template id and image id required")
+	}
+
+	if err := c.service.DeleteImage(userID, id, imageID); err != nil {
+		if errors.Is(err, ErrTemplateNotFound) {
+			return utils.SendError(ctx, fiber.StatusNotFound, err.Error())
+		}
+		return utils.SendError(ctx, fiber.StatusBadRequest, err.Error())
+	}
+	return utils.SendSuccess(ctx, nil, "image deleted successfully")
+}
+
+func (c *Controller) CreateOrder(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	id := ctx.Params("id")
+	if id == "" {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "id required")
+	}
+
+	var req CreateOrderRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "invalid request body")
+	}
+
+	if err := c.validate.Struct(req); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, utils.ParseValidationError(err))
+	}
+
+	res, err := c.service.CreateOrder(userID, id, req)
+	if err != nil {
+		if errors.Is(err, ErrTemplateNotFound) {
+			return utils.SendError(ctx, fiber.StatusNotFound, err.Error())
+		}
+		if errors.Is(err, products.ErrPriceMismatch) {
+			return utils.SendError(ctx, fiber.StatusBadRequest, err.Error())
+		}
+		return utils.SendError(ctx, fiber.StatusInternalServerError, err.Error())
+	}
+	return utils.SendCreated(ctx, res, "product created successfully")
+}
+
+func getUserIDFromToken(ctx *fiber.Ctx) (string, error) {
+	userToken := ctx.Locals("user")
+	if userToken == nil {
+		return "", fmt.Errorf("no user in context")
+	}
+
+	token, ok := userToken.(*jwt.Token)
+	if !ok {
+		return "", fmt.Errorf("invalid token type")
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return "", fmt.Errorf("invalid claims")
+	}
+
+	switch v := claims["user_id"].(type) {
+	case string:
+		return v, nil
+	case float64:
+		return fmt.Sprintf("%.0f", v), nil
+	default:
+		return "", fmt.Errorf("invalid user_id type in token")
+	}
+}
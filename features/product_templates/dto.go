@@ -0,0 +1,71 @@
+package product_templates
+
+import "github.com/TFX0019/api-go-gds/pkg/utils"
+
+type CreateTemplateRequest struct {
+	Name                 string                    `json:"name" validate:"required,max=100"`
+	Description          string                    `json:"description" validate:"max=500"`
+	Materials            []TemplateMaterialRequest `json:"materials" validate:"omitempty,dive"`
+	EstimatedHours       float64                   `json:"estimated_hours" validate:"gte=0"`
+	HoursCost            utils.Money               `json:"hours_cost" validate:"gte=0"`
+	ProfitPercentage     float64                   `json:"profit_percentage" validate:"gte=0"`
+	IncludeFixedExpenses bool                      `json:"include_fixed_expenses"`
+	FixedExpenseRate     float64                   `json:"fixed_expense_rate" validate:"gte=0"`
+}
+
+type UpdateTemplateRequest struct {
+	Name        *string `json:"name" validate:"omitempty,max=100"`
+	Description *string `json:"description" validate:"omitempty,max=500"`
+	// Replaces the materials when sent, an empty list removes them
+	Materials            *[]TemplateMaterialRequest `json:"materials" validate:"omitempty,dive"`
+	EstimatedHours       *float64                   `json:"estimated_hours" validate:"omitempty,gte=0"`
+	HoursCost            *utils.Money               `json:"hours_cost" validate:"omitempty,gte=0"`
+	ProfitPercentage     *float64                   `json:"profit_percentage" validate:"omitempty,gte=0"`
+	IncludeFixedExpenses *bool                      `json:"include_fixed_expenses"`
+	FixedExpenseRate     *float64                   `json:"fixed_expense_rate" validate:"omitempty,gte=0"`
+}
+
+type TemplateMaterialRequest struct {
+	MaterialID string  `json:"material_id" validate:"required,uuid"`
+	Quantity   float64 `json:"quantity" validate:"gt=0"`
+}
+
+// CreateOrderRequest creates an order from a template; everything else is
+// taken from the template.
+type CreateOrderRequest struct {
+	Name                  string  `json:"name" validate:"max=100"` // Defaults to the template's name
+	ClientID              *string `json:"client_id" validate:"omitempty,uuid"`
+	MeasurementSnapshotID *string `json:"measurement_snapshot_id" validate:"omitempty,uuid"`
+	DueDate               *string `json:"due_date" validate:"omitempty,datetime=2006-01-02"`
+	TaxProfileID          *string `json:"tax_profile_id" validate:"omitempty,uuid"`
+}
+
+type TemplateMaterialResponse struct {
+	MaterialID string      `json:"material_id"`
+	Name       string      `json:"name"`
+	Unit       string      `json:"unit"`
+	UnitPrice  utils.Money `json:"unit_price"`
+	Currency   string      `json:"currency"`
+	Quantity   float64     `json:"quantity"`
+}
+
+type TemplateImageResponse struct {
+	ID        string `json:"id"`
+	Path      string `json:"path"`
+	CreatedAt string `json:"created_at"`
+}
+
+type TemplateResponse struct {
+	ID                   string                     `json:"id"`
+	Name                 string                     `json:"name"`
+	Description          string                     `json:"description"`
+	Materials            []TemplateMaterialResponse `json:"materials"`
+	EstimatedHours       float64                    `json:"estimated_hours"`
+	HoursCost            utils.Money                `json:"hours_cost"`
+	ProfitPercentage     float64                    `json:"profit_percentage"`
+	IncludeFixedExpenses bool                       `json:"include_fixed_expenses"`
+	FixedExpenseRate     float64                    `json:"fixed_expense_rate"`
+	Images               []TemplateImageResponse    `json:"images"`
+	CreatedAt            string                     `json:"created_at"`
+	UpdatedAt            string                     `json:"updated_at"`
+}
@@ -0,0 +1,56 @@
+package product_templates
+
+import (
+	"time"
+
+	"github.com/TFX0019/api-go-gds/features/materials"
+	"github.com/TFX0019/api-go-gds/pkg/utils"
+	"github.com/google/uuid"
+)
+
+// ProductTemplate is a garment the user makes often, with the materials,
+// hours and margin new orders for it start from.
+type ProductTemplate struct {
+	ID                   uuid.UUID          `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	UserID               uint               `gorm:"not null;index"`
+	Name                 string             `gorm:"type:text;not null"`
+	Description          string             `gorm:"type:text"`
+	EstimatedHours       float64            `gorm:"type:numeric;not null;default:0"`
+	HoursCost            utils.Money        `gorm:"type:numeric;not null;default:0"`
+	ProfitPercentage     float64            `gorm:"type:numeric;not null;default:0"`
+	IncludeFixedExpenses bool               `gorm:"not null;default:false"`
+	FixedExpenseRate     float64            `gorm:"type:numeric;not null;default:0"`
+	Materials            []TemplateMaterial `gorm:"foreignKey:TemplateID;constraint:OnDelete:CASCADE;"`
+	Images               []TemplateImage    `gorm:"foreignKey:TemplateID;constraint:OnDelete:CASCADE;"`
+	CreatedAt            time.Time          `gorm:"not null;default:now()"`
+	UpdatedAt            time.Time          `gorm:"not null;default:now()"`
+}
+
+func (ProductTemplate) TableName() string {
+	return "product_templates"
+}
+
+// TemplateMaterial is a default bill of materials line. Only the quantity is
+// kept: orders price the material when they are created.
+type TemplateMaterial struct {
+	ID         uuid.UUID           `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	TemplateID uuid.UUID           `gorm:"type:uuid;not null;index"`
+	MaterialID uuid.UUID           `gorm:"type:uuid;not null"`
+	Material   *materials.Material `gorm:"foreignKey:MaterialID;constraint:OnDelete:CASCADE;"`
+	Quantity   float64             `gorm:"type:numeric;not null"`
+}
+
+func (TemplateMaterial) TableName() string {
+	return "product_template_materials"
+}
+
+type TemplateImage struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	TemplateID uuid.UUID `gorm:"type:uuid;not null;index"`
+	Path       string    `gorm:"type:text;not null"`
+	CreatedAt  time.Time `gorm:"not null;default:now()"`
+}
+
+func (TemplateImage) TableName() string {
+	return "product_template_images"
+}
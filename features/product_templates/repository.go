@@ -0,0 +1,82 @@
+package product_templates
+
+import (
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	Create(template *ProductTemplate) error
+	FindByUserID(userID uint) ([]ProductTemplate, error)
+	FindByID(id string) (*ProductTemplate, error)
+	Update(template *ProductTemplate, items []TemplateMaterial) error
+	Delete(id string) error
+	AddImage(image *TemplateImage) error
+	DeleteImage(id string) error
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) Create(template *ProductTemplate) error {
+	return r.db.Create(template).Error
+}
+
+func (r *repository) FindByUserID(userID uint) ([]ProductTemplate, error) {
+	var templates []ProductTemplate
+	err := r.db.Preload("Materials.Material").Preload("Images").
+		Where("user_id = ?", userID).Order("name asc").Find(&templates).Error
+	return templates, err
+}
+
+func (r *repository) FindByID(id string) (*ProductTemplate, error) {
+	var template ProductTemplate
+	err := r.db.Preload("Materials.Material").Preload("Images").First(&template, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// Update saves the template and replaces its materials when items is not
+// nil.
+func (r *repository) Update(template *ProductTemplate, items []TemplateMaterial) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Materials", "Images").Save(template).Error; err != nil {
+			return err
+		}
+
+		if items == nil {
+			return nil
+		}
+		if err := tx.Where("template_id = ?", template.ID).Delete(&TemplateMaterial{}).Error; err != nil {
+			return err
+		}
+		for i := range items {
+			items[i].TemplateID = template.ID
+		}
+		if len(items) > 0 {
+			if err := tx.Omit("Material").Create(&items).Error; err != nil {
+				return err
+			}
+		}
+		template.Materials = items
+		return nil
+	})
+}
+
+func (r *repository) Delete(id string) error {
+	return r.db.Delete(&ProductTemplate{}, "id = ?", id).Error
+}
+
+func (r *repository) AddImage(image *TemplateImage) error {
+	return r.db.Create(image).Error
+}
+
+func (r *repository) DeleteImage(id string) error {
+	return r.db.Delete(&TemplateImage{}, "id = ?", id).Error
+}
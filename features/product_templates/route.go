@@ -0,0 +1,19 @@
+package product_templates
+
+import (
+	"github.com/TFX0019/api-go-gds/pkg/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+func RegisterRoutes(app fiber.Router, controller *Controller) {
+	route := app.Group("/api/product-templates", middleware.Protected())
+
+	route.Get("/", controller.GetAll)
+	route.Post("/", controller.Create)
+	route.Get("/:id", controller.GetByID)
+	route.Put("/:id", controller.Update)
+	route.Delete("/:id", controller.Delete)
+	route.Post("/:id/images", controller.UploadImages)
+	route.Delete("/:id/images/:image_id", controller.DeleteImage)
+	route.Post("/:id/products", controller.CreateOrder)
+}
@@ -0,0 +1,362 @@
+package product_templates
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/TFX0019/api-go-gds/features/materials"
+	"github.com/TFX0019/api-go-gds/features/products"
+	"github.com/google/uuid"
+)
+
+var ErrTemplateNotFound = errors.New("template not found")
+
+// maxImages matches the number of images an order can have, so every
+// reference image reaches the orders made from the template.
+const maxImages = 5
+
+type Service interface {
+	GetAll(userID string) ([]TemplateResponse, error)
+	GetByID(userID, id string) (*TemplateResponse, error)
+	Create(userID string, req CreateTemplateRequest) (*TemplateResponse, error)
+	Update(userID, id string, req UpdateTemplateRequest) (*TemplateResponse, error)
+	Delete(userID, id string) error
+	AddImages(userID, id string, paths []string) ([]TemplateImageResponse, error)
+	DeleteImage(userID, id, imageID string) error
+	CreateOrder(userID, id string, req CreateOrderRequest) (*products.ProductResponse, error)
+}
+
+type service struct {
+	repo            Repository
+	materialsRepo   materials.Repository
+	productsService products.Service
+}
+
+func NewService(repo Repository, materialsRepo materials.Repository, productsService products.Service) Service {
+	return &service{repo: repo, materialsRepo: materialsRepo, productsService: productsService}
+}
+
+func (s *service) GetAll(userID string) ([]TemplateResponse, error) {
+	uid, err := strconv.ParseUint(userID, 10, 32)
+	if err != nil {
+		return nil, errors.New("invalid user id")
+	}
+
+	templates, err := s.repo.FindByUserID(uint(uid))
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]TemplateResponse, 0, len(templates))
+	for _, t := range templates {
+		res = append(res, mapToResponse(t))
+	}
+	return res, nil
+}
+
+func (s *service) GetByID(userID, id string) (*TemplateResponse, error) {
+	template, err := s.findOwned(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	res := mapToResponse(*template)
+	return &res, nil
+}
+
+func (s *service) Create(userID string, req CreateTemplateRequest) (*TemplateResponse, error) {
+	uid, err := strconv.ParseUint(userID, 10, 32)
+	if err != nil {
+		return nil, errors.New("invalid user id")
+	}
+
+	items, err := s.resolveMaterials(uint(uid), req.Materials)
+	if err != nil {
+		return nil, err
+	}
+
+	template := &ProductTemplate{
+		ID:                   uuid.New(),
+		UserID:               uint(uid),
+		Name:                 strings.TrimSpace(req.Name),
+		Description:          strings.TrimSpace(req.Description),
+		EstimatedHours:       req.EstimatedHours,
+		HoursCost:            req.HoursCost.Round(),
+		ProfitPercentage:     req.ProfitPercentage,
+		IncludeFixedExpenses: req.IncludeFixedExpenses,
+		FixedExpenseRate:     req.FixedExpenseRate,
+		Materials:            items,
+	}
+	if err := s.repo.Create(template); err != nil {
+		return nil, err
+	}
+
+	res := mapToResponse(*template)
+	return &res, nil
+}
+
+func (s *service) Update(userID, id string, req UpdateTemplateRequest) (*TemplateResponse, error) {
+	template, err := s.findOwned(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// nil keeps the current materials
+	var items []TemplateMaterial
+	if req.Materials != nil {
+		if items, err = s.resolveMaterials(template.UserID, *req.Materials); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.Name != nil && strings.TrimSpace(*req.Name) != "" {
+		template.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Description != nil {
+		template.Description = strings.TrimSpace(*req.Description)
+	}
+	if req.EstimatedHours != nil {
+		template.EstimatedHours = *req.EstimatedHours
+	}
+	if req.HoursCost != nil {
+		template.HoursCost = req.HoursCost.Round()
+	}
+	if req.ProfitPercentage != nil {
+		template.ProfitPercentage = *req.ProfitPercentage
+	}
+	if req.IncludeFixedExpenses != nil {
+		template.IncludeFixedExpenses = *req.IncludeFixedExpenses
+	}
+	if req.FixedExpenseRate != nil {
+		template.FixedExpenseRate = *req.FixedExpenseRate
+	}
+
+	if err := s.repo.Update(template, items); err != nil {
+		return nil, err
+	}
+
+	res := mapToResponse(*template)
+	return &res, nil
+}
+
+func (s *service) Delete(userID, id string) error {
+	template, err := s.findOwned(userID, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(id); err != nil {
+		return err
+	}
+
+	// Orders made from the template have their own copies
+	for _, image := range template.Images {
+		os.Remove(image.Path)
+	}
+	return nil
+}
+
+func (s *service) AddImages(userID, id string, paths []string) ([]TemplateImageResponse, error) {
+	template, err := s.findOwned(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(template.Images)+len(paths) > maxImages {
+		return nil, fmt.Errorf("maximum of %d images allowed per template", maxImages)
+	}
+
+	res := []TemplateImageResponse{}
+	for _, path := range paths {
+		image := &TemplateImage{TemplateID: template.ID, Path: path}
+		if err := s.repo.AddImage(image); err != nil {
+			return nil, err
+		}
+		res = append(res, mapImageToResponse(*image))
+	}
+	return res, nil
+}
+
+func (s *service) DeleteImage(userID, id, imageID string) error {
+	template, err := s.findOwned(userID, id)
+	if err != nil {
+		return err
+	}
+
+	for _, image := range template.Images {
+		if image.ID.String() == imageID {
+			if err := s.repo.DeleteImage(imageID); err != nil {
+				return err
+			}
+			os.Remove(image.Path)
+			return nil
+		}
+	}
+	return errors.New("image does not belong to this template")
+}
+
+// CreateOrder creates an order for the template's garment. It goes through
+// the products service, so the plan's product limit and the current prices
+// of the materials apply as for any other order.
+func (s *service) CreateOrder(userID, id string, req CreateOrderRequest) (*products.ProductResponse, error) {
+	template, err := s.findOwned(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		name = template.Name
+	}
+
+	lines := []products.ProductMaterialRequest{}
+	for _, item := range template.Materials {
+		lines = append(lines, products.ProductMaterialRequest{
+			MaterialID: item.MaterialID.String(),
+			Quantity:   item.Quantity,
+		})
+	}
+
+	// The order gets its own copy of each image, since deleting an order's
+	// image removes the file
+	var images []string
+	for _, image := range template.Images {
+		path, err := copyImage(image.Path)
+		if err != nil {
+			removeImages(images)
+			return nil, err
+		}
+		images = append(images, path)
+	}
+
+	product, err := s.productsService.Create(userID, products.CreateProductRequest{
+		Name:                  name,
+		ClientID:              req.ClientID,
+		MeasurementSnapshotID: req.MeasurementSnapshotID,
+		DueDate:               req.DueDate,
+		Materials:             lines,
+		HoursCost:             template.HoursCost,
+		EstimatedHours:        template.EstimatedHours,
+		ProfitPercentage:      template.ProfitPercentage,
+		IncludeFixedExpenses:  template.IncludeFixedExpenses,
+		FixedExpenseRate:      template.FixedExpenseRate,
+		TaxProfileID:          req.TaxProfileID,
+		Images:                images,
+	})
+	if err != nil {
+		removeImages(images)
+		return nil, err
+	}
+	return product, nil
+}
+
+// resolveMaterials checks each requested material belongs to the user.
+func (s *service) resolveMaterials(userID uint, lines []TemplateMaterialRequest) ([]TemplateMaterial, error) {
+	items := []TemplateMaterial{}
+	for _, line := range lines {
+		material, err := s.materialsRepo.FindByID(line.MaterialID)
+		if err != nil || material.UserID != userID {
+			return nil, fmt.Errorf("material %s not found", line.MaterialID)
+		}
+		items = append(items, TemplateMaterial{
+			MaterialID: material.ID,
+			Material:   material,
+			Quantity:   line.Quantity,
+		})
+	}
+	return items, nil
+}
+
+func (s *service) findOwned(userID, id string) (*ProductTemplate, error) {
+	template, err := s.repo.FindByID(id)
+	if err != nil || fmt.Sprintf("%d", template.UserID) != userID {
+		return nil, ErrTemplateNotFound
+	}
+	return template, nil
+}
+
+// copyImage copies an uploaded image to a new file under uploads and
+// returns its path.
+func copyImage(path string) (string, error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return "", errors.New("failed to copy template image")
+	}
+	defer src.Close()
+
+	name := filepath.Base(path)
+	// Uploads are named <uuid>-<original name>
+	if len(name) > 37 && name[36] == '-' {
+		name = name[37:]
+	}
+	copyPath := fmt.Sprintf("uploads/%s-%s", uuid.New().String(), name)
+
+	dst, err := os.Create(copyPath)
+	if err != nil {
+		return "", errors.New("failed to copy template image")
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		os.Remove(copyPath)
+		return "", errors.New("failed to copy template image")
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(copyPath)
+		return "", errors.New("failed to copy template image")
+	}
+	return copyPath, nil
+}
+
+func removeImages(paths []string) {
+	for _, path := range paths {
+		os.Remove(path)
+	}
+}
+
+func mapToResponse(t ProductTemplate) TemplateResponse {
+	lines := []TemplateMaterialResponse{}
+	for _, item := range t.Materials {
+		line := TemplateMaterialResponse{
+			MaterialID: item.MaterialID.String(),
+			Quantity:   item.Quantity,
+		}
+		if item.Material != nil {
+			line.Name = item.Material.Name
+			line.Unit = item.Material.Unit
+			line.UnitPrice = item.Material.Price
+			line.Currency = item.Material.Currency
+		}
+		lines = append(lines, line)
+	}
+
+	images := []TemplateImageResponse{}
+	for _, image := range t.Images {
+		images = append(images, mapImageToResponse(image))
+	}
+
+	return TemplateResponse{
+		ID:                   t.ID.String(),
+		Name:                 t.Name,
+		Description:          t.Description,
+		Materials:            lines,
+		EstimatedHours:       t.EstimatedHours,
+		HoursCost:            t.HoursCost,
+		ProfitPercentage:     t.ProfitPercentage,
+		IncludeFixedExpenses: t.IncludeFixedExpenses,
+		FixedExpenseRate:     t.FixedExpenseRate,
+		Images:               images,
+		CreatedAt:            t.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:            t.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+func mapImageToResponse(image TemplateImage) TemplateImageResponse {
+	return TemplateImageResponse{
+		ID:        image.ID.String(),
+		Path:      image.Path,
+		CreatedAt: image.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
@@ -0,0 +1,111 @@
+package products
+
+import (
+	"fmt"
+
+	"github.com/TFX0019/api-go-gds/pkg/utils"
+)
+
+const (
+	AdjustmentDiscount  = "discount"
+	AdjustmentSurcharge = "surcharge"
+
+	AdjustmentPercentage = "percentage"
+	AdjustmentFixed      = "fixed"
+)
+
+// adjustmentAmounts returns the amount of each adjustment on an order whose
+// price with profit is price. Percentages are taken from that price, and
+// discounts are capped so the order never goes below zero.
+func adjustmentAmounts(price utils.Money, adjustments []AdjustmentRequest) []utils.Money {
+	amounts := make([]utils.Money, len(adjustments))
+	left := price
+	for i, a := range adjustments {
+		if a.Kind == AdjustmentSurcharge {
+			amounts[i] = adjustmentAmount(price, a)
+			left = left.Add(amounts[i])
+		}
+	}
+	for i, a := range adjustments {
+		if a.Kind == AdjustmentDiscount {
+			amounts[i] = utils.MinMoney(adjustmentAmount(price, a), left)
+			left = left.Sub(amounts[i])
+		}
+	}
+	return amounts
+}
+
+func adjustmentAmount(price utils.Money, a AdjustmentRequest) utils.Money {
+	if a.Type == AdjustmentPercentage {
+		return price.Percent(a.Value).Round()
+	}
+	return utils.NewMoney(a.Value).Round()
+}
+
+// adjustmentPrice is the order's price with profit, which its adjustments
+// are applied to.
+func (p Product) adjustmentPrice() utils.Money {
+	return p.BaseTotal.Add(p.BaseTotal.Percent(p.ProfitPercentage).Round())
+}
+
+func newAdjustments(reqs []AdjustmentRequest) []ProductAdjustment {
+	adjustments := []ProductAdjustment{}
+	for i, r := range reqs {
+		adjustments = append(adjustments, ProductAdjustment{
+			Kind:     r.Kind,
+			Type:     r.Type,
+			Value:    r.Value,
+			Reason:   r.Reason,
+			Position: i,
+		})
+	}
+	return adjustments
+}
+
+func adjustmentRequests(adjustments []ProductAdjustment) []AdjustmentRequest {
+	reqs := make([]AdjustmentRequest, 0, len(adjustments))
+	for _, a := range adjustments {
+		reqs = append(reqs, AdjustmentRequest{
+			Kind:   a.Kind,
+			Type:   a.Type,
+			Value:  a.Value,
+			Reason: a.Reason,
+		})
+	}
+	return reqs
+}
+
+func mapAdjustmentsToResponse(p Product) []AdjustmentResponse {
+	amounts := adjustmentAmounts(p.adjustmentPrice(), adjustmentRequests(p.Adjustments))
+	res := make([]AdjustmentResponse, 0, len(p.Adjustments))
+	for i, a := range p.Adjustments {
+		res = append(res, AdjustmentResponse{
+			ID:     a.ID.String(),
+			Kind:   a.Kind,
+			Type:   a.Type,
+			Value:  a.Value,
+			Reason: a.Reason,
+			Amount: amounts[i],
+		})
+	}
+	return res
+}
+
+// adjustmentLines lists the adjustments the way documents print them, with
+// discounts as negative amounts.
+func adjustmentLines(p Product) []documentLine {
+	amounts := adjustmentAmounts(p.adjustmentPrice(), adjustmentRequests(p.Adjustments))
+	var lines []documentLine
+	for i, a := range p.Adjustments {
+		description := a.Reason
+		if a.Type == AdjustmentPercentage {
+			description = fmt.Sprintf("%s (%s%%)", a.Reason, formatQuantity(a.Value))
+		}
+		amount := amounts[i]
+		if a.Kind == AdjustmentDiscount {
+			amount = amount.Neg()
+		}
+		lines = append(lines, fixedLine(description, amount))
+	}
+	return lines
+}
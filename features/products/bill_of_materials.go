@@ -0,0 +1,153 @@
+package products
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/TFX0019/api-go-gds/features/exchange_rates"
+	"github.com/TFX0019/api-go-gds/features/materials"
+	"github.com/TFX0019/api-go-gds/pkg/utils"
+	"github.com/google/uuid"
+)
+
+// resolveMaterials turns the requested lines into bill of materials items,
+// checking each material belongs to the user.
+func (s *service) resolveMaterials(userID uint, currency string, lines []ProductMaterialRequest) ([]ProductMaterial, error) {
+	var rates map[string]float64
+	items := []ProductMaterial{}
+	for _, line := range lines {
+		if line.Quantity <= 0 {
+			return nil, errors.New("material quantities must be greater than zero")
+		}
+
+		material, err := s.materialsRepo.FindByID(line.MaterialID)
+		if err != nil || material.UserID != userID {
+			return nil, fmt.Errorf("material %s not found", line.MaterialID)
+		}
+
+		// Materials bought in another currency are priced in the order's
+		// currency at the current exchange rate
+		unitPrice := material.Price
+		if material.Currency != currency {
+			if rates == nil {
+				if rates, err = s.ratesRepo.Rates(); err != nil {
+					return nil, err
+				}
+			}
+			if unitPrice, err = exchange_rates.Convert(rates, material.Price, material.Currency, currency); err != nil {
+				return nil, err
+			}
+			unitPrice = unitPrice.Round()
+		}
+
+		items = append(items, ProductMaterial{
+			MaterialID: &material.ID,
+			Material:   material,
+			Name:       material.Name,
+			Unit:       material.Unit,
+			UnitPrice:  unitPrice,
+			Quantity:   line.Quantity,
+		})
+	}
+	return items, nil
+}
+
+func materialsCost(items []ProductMaterial) utils.Money {
+	var total utils.Money
+	for _, item := range items {
+		total = total.Add(item.cost())
+	}
+	return total
+}
+
+// cost is the line's price for its quantity, rounded to cents.
+func (item ProductMaterial) cost() utils.Money {
+	return item.UnitPrice.Mul(item.Quantity).Round()
+}
+
+// stockChanges returns how much each material's stock moves when the items
+// are consumed (sign -1) or given back (sign 1).
+func stockChanges(items []ProductMaterial, sign float64, changes map[uuid.UUID]float64) map[uuid.UUID]float64 {
+	if changes == nil {
+		changes = map[uuid.UUID]float64{}
+	}
+	for _, item := range items {
+		// Lines of deleted materials have nothing to move
+		if item.MaterialID != nil {
+			changes[*item.MaterialID] += sign * item.Quantity
+		}
+	}
+	return changes
+}
+
+// stockWarnings lists the materials whose stock does not cover the order.
+// Before consumption the stock must cover the required quantity; after it,
+// a negative stock means the order used more than was available.
+func stockWarnings(p Product) []StockWarningResponse {
+	if p.Status == StatusCancelled {
+		return nil
+	}
+
+	required := map[uuid.UUID]float64{}
+	var order []uuid.UUID
+	for _, item := range p.Materials {
+		if item.MaterialID == nil || item.Material == nil {
+			continue
+		}
+		if _, seen := required[*item.MaterialID]; !seen {
+			order = append(order, *item.MaterialID)
+		}
+		required[*item.MaterialID] += item.Quantity
+	}
+
+	var warnings []StockWarningResponse
+	for _, id := range order {
+		material := materialOf(p.Materials, id)
+		available := material.Quantity
+		if p.StockConsumed {
+			// The stock already excludes this order
+			available += required[id]
+		}
+		if available < required[id] {
+			warnings = append(warnings, StockWarningResponse{
+				MaterialID: id.String(),
+				Name:       material.Name,
+				Unit:       material.Unit,
+				Required:   required[id],
+				Available:  available,
+			})
+		}
+	}
+	return warnings
+}
+
+func materialOf(items []ProductMaterial, id uuid.UUID) *materials.Material {
+	for _, item := range items {
+		if item.MaterialID != nil && *item.MaterialID == id && item.Material != nil {
+			return item.Material
+		}
+	}
+	return &materials.Material{}
+}
+
+func mapMaterialsToResponse(items []ProductMaterial) []ProductMaterialResponse {
+	res := make([]ProductMaterialResponse, 0, len(items))
+	for _, item := range items {
+		var materialID *string
+		if item.MaterialID != nil {
+			s := item.MaterialID.String()
+			materialID = &s
+		}
+
+		res = append(res, ProductMaterialResponse{
+			ID:         item.ID.String(),
+			MaterialID: materialID,
+			Name:       item.Name,
+			Unit:       item.Unit,
+			UnitPrice:  item.UnitPrice,
+			Quantity:   item.Quantity,
+			Cost:       item.cost(),
+		})
+	}
+	return res
+}
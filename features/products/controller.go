@@ -1,11 +1,13 @@
 package products
 
 import (
+	"errors"
 	"fmt"
 	"path/filepath"
 	"strconv"
 	"strings"
 
+	"github.com/TFX0019/api-go-gds/features/exchange_rates"
 	"github.com/TFX0019/api-go-gds/pkg/utils"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
@@ -21,7 +23,7 @@ type Controller struct {
 func NewController(service Service) *Controller {
 	return &Controller{
 		service:  service,
-		validate: validator.New(),
+		validate: utils.NewValidator(),
 	}
 }
 
@@ -42,12 +44,28 @@ func (c *Controller) Create(ctx *fiber.Ctx) error {
 
 	productRes, err := c.service.Create(userID, req)
 	if err != nil {
+		if errors.Is(err, ErrPriceMismatch) {
+			return utils.SendError(ctx, fiber.StatusBadRequest, err.Error())
+		}
 		return utils.SendError(ctx, fiber.StatusInternalServerError, err.Error())
 	}
 
 	return utils.SendCreated(ctx, productRes, "product created successfully")
 }
 
+func (c *Controller) PreviewPrice(ctx *fiber.Ctx) error {
+	var req PricingRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "invalid request body")
+	}
+
+	if err := c.validate.Struct(req); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, utils.ParseValidationError(err))
+	}
+
+	return utils.SendSuccess(ctx, c.service.PreviewPrice(req), "price calculated successfully")
+}
+
 func (c *Controller) GetAll(ctx *fiber.Ctx) error {
 	var query PaginationQuery
 	if err := ctx.QueryParser(&query); err != nil {
@@ -109,33 +127,133 @@ func (c *Controller) GetByUserID(ctx *fiber.Ctx) error {
 	return utils.SendSuccess(ctx, res, "user products retrieved successfully")
 }
 
-func (c *Controller) GetProfitLoss(ctx *fiber.Ctx) error {
+func (c *Controller) GetUpcoming(ctx *fiber.Ctx) error {
+	return c.getDue(ctx, false)
+}
+
+func (c *Controller) GetOverdue(ctx *fiber.Ctx) error {
+	return c.getDue(ctx, true)
+}
+
+func (c *Controller) getDue(ctx *fiber.Ctx, overdue bool) error {
 	userID, err := getUserIDFromToken(ctx)
 	if err != nil {
 		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
 	}
 
-	monthStr := ctx.Query("month")
-	var month int
-	if monthStr != "" {
-		month = parseMonth(monthStr)
-		if month == 0 {
-			// Optional: Return error if invalid month provided?
-			// Or just ignore filter?
-			// prompt said "filter by month example ?month=jan", implying valid input.
-			// I'll ignore invalid input or better, return error.
-			// Let's just pass 0 if invalid.
-		}
+	var query DueOrdersQuery
+	if err := ctx.QueryParser(&query); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "invalid query parameters")
+	}
+
+	if err := c.validate.Struct(query); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, utils.ParseValidationError(err))
+	}
+
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.Limit < 1 {
+		query.Limit = 10
 	}
 
-	res, err := c.service.GetProfitLoss(userID, month)
+	var res *PaginatedResponse
+	if overdue {
+		res, err = c.service.GetOverdue(userID, query.Page, query.Limit)
+	} else {
+		res, err = c.service.GetUpcoming(userID, query.Days, query.Page, query.Limit)
+	}
 	if err != nil {
 		return utils.SendError(ctx, fiber.StatusInternalServerError, err.Error())
 	}
 
+	return utils.SendSuccess(ctx, res, "products retrieved successfully")
+}
+
+func (c *Controller) GetProfitLoss(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	var query ProfitLossQuery
+	if err := ctx.QueryParser(&query); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "invalid query parameters")
+	}
+
+	if err := c.validate.Struct(query); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, utils.ParseValidationError(err))
+	}
+
+	res, err := c.service.GetProfitLoss(userID, query)
+	if err != nil {
+		if errors.Is(err, exchange_rates.ErrMissingRate) {
+			return utils.SendError(ctx, fiber.StatusUnprocessableEntity, err.Error())
+		}
+		return utils.SendError(ctx, fiber.StatusBadRequest, err.Error())
+	}
+
 	return utils.SendSuccess(ctx, res, "profit and loss retrieved successfully")
 }
 
+func (c *Controller) ExportProfitLoss(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	var query ProfitLossQuery
+	if err := ctx.QueryParser(&query); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "invalid query parameters")
+	}
+
+	if err := c.validate.Struct(query); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, utils.ParseValidationError(err))
+	}
+
+	data, err := c.service.ExportProfitLoss(userID, query)
+	if err != nil {
+		if errors.Is(err, exchange_rates.ErrMissingRate) {
+			return utils.SendError(ctx, fiber.StatusUnprocessableEntity, err.Error())
+		}
+		return utils.SendError(ctx, fiber.StatusBadRequest, err.Error())
+	}
+
+	if query.Format == "xlsx" {
+		ctx.Attachment("profit-loss.xlsx")
+	} else {
+		ctx.Attachment("profit-loss.csv")
+		ctx.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	}
+	return ctx.Send(data)
+}
+
+func (c *Controller) GetTaxReport(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	var query TaxReportQuery
+	if err := ctx.QueryParser(&query); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "invalid query parameters")
+	}
+
+	if err := c.validate.Struct(query); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, utils.ParseValidationError(err))
+	}
+
+	res, err := c.service.GetTaxReport(userID, query)
+	if err != nil {
+		if errors.Is(err, exchange_rates.ErrMissingRate) {
+			return utils.SendError(ctx, fiber.StatusUnprocessableEntity, err.Error())
+		}
+		return utils.SendError(ctx, fiber.StatusBadRequest, err.Error())
+	}
+
+	return utils.SendSuccess(ctx, res, "tax report retrieved successfully")
+}
+
 func parseMonth(m string) int {
 	if m == "" {
 		return 0
@@ -185,8 +303,18 @@ func (c *Controller) Update(ctx *fiber.Ctx) error {
 		return utils.SendError(ctx, fiber.StatusBadRequest, "invalid request body")
 	}
 
+	if err := c.validate.Struct(req); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, utils.ParseValidationError(err))
+	}
+
 	productRes, err := c.service.Update(id, req)
 	if err != nil {
+		if errors.Is(err, ErrPriceMismatch) {
+			return utils.SendError(ctx, fiber.StatusBadRequest, err.Error())
+		}
+		if errors.Is(err, ErrPriceAccepted) {
+			return utils.SendError(ctx, fiber.StatusConflict, err.Error())
+		}
 		return utils.SendError(ctx, fiber.StatusInternalServerError, err.Error())
 	}
 
@@ -207,6 +335,11 @@ func (c *Controller) Delete(ctx *fiber.Ctx) error {
 }
 
 func (c *Controller) UpdateStatus(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
 	id := ctx.Params("id")
 	if id == "" {
 		return utils.SendError(ctx, fiber.StatusBadRequest, "id required")
@@ -221,14 +354,638 @@ func (c *Controller) UpdateStatus(ctx *fiber.Ctx) error {
 		return utils.SendError(ctx, fiber.StatusBadRequest, utils.ParseValidationError(err))
 	}
 
-	productRes, err := c.service.UpdateStatus(id, req.Status)
+	productRes, err := c.service.UpdateStatus(userID, id, req)
 	if err != nil {
+		if errors.Is(err, ErrProductNotFound) {
+			return utils.SendError(ctx, fiber.StatusNotFound, err.Error())
+		}
+		if errors.Is(err, ErrInvalidTransition) {
+			return utils.SendError(ctx, fiber.StatusConflict, err.Error())
+		}
 		return utils.SendError(ctx, fiber.StatusInternalServerError, err.Error())
 	}
 
 	return utils.SendSuccess(ctx, productRes, "product status updated successfully")
 }
 
+func (c *Controller) GetStatusHistory(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	id := ctx.Params("id")
+	if id == "" {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "id required")
+	}
+
+	res, err := c.service.GetStatusHistory(userID, id)
+	if err != nil {
+		if errors.Is(err, ErrProductNotFound) {
+			return utils.SendError(ctx, fiber.StatusNotFound, err.Error())
+		}
+		return utils.SendError(ctx, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(ctx, res, "status history retrieved successfully")
+}
+
+func (c *Controller) CheckStock(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	id := ctx.Params("id")
+	if id == "" {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "id required")
+	}
+
+	res, err := c.service.CheckStock(userID, id)
+	if err != nil {
+		if errors.Is(err, ErrProductNotFound) {
+			return utils.SendError(ctx, fiber.StatusNotFound, err.Error())
+		}
+		return utils.SendError(ctx, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(ctx, res, "stock checked successfully")
+}
+
+func (c *Controller) GetTimeTracking(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	id := ctx.Params("id")
+	if id == "" {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "id required")
+	}
+
+	res, err := c.service.GetTimeTracking(userID, id)
+	if err != nil {
+		if errors.Is(err, ErrProductNotFound) {
+			return utils.SendError(ctx, fiber.StatusNotFound, err.Error())
+		}
+		return utils.SendError(ctx, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(ctx, res, "time entries retrieved successfully")
+}
+
+func (c *Controller) StartTimer(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	id := ctx.Params("id")
+	if id == "" {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "id required")
+	}
+
+	var req StartTimerRequest
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(&req); err != nil {
+			return utils.SendError(ctx, fiber.StatusBadRequest, "invalid request body")
+		}
+	}
+
+	if err := c.validate.Struct(req); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, utils.ParseValidationError(err))
+	}
+
+	res, err := c.service.StartTimer(userID, id, req)
+	if err != nil {
+		if errors.Is(err, ErrProductNotFound) {
+			return utils.SendError(ctx, fiber.StatusNotFound, err.Error())
+		}
+		return utils.SendError(ctx, fiber.StatusBadRequest, err.Error())
+	}
+
+	return utils.SendCreated(ctx, res, "timer started successfully")
+}
+
+func (c *Controller) StopTimer(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	id := ctx.Params("id")
+	if id == "" {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "id required")
+	}
+
+	res, err := c.service.StopTimer(userID, id)
+	if err != nil {
+		if errors.Is(err, ErrProductNotFound) {
+			return utils.SendError(ctx, fiber.StatusNotFound, err.Error())
+		}
+		return utils.SendError(ctx, fiber.StatusBadRequest, err.Error())
+	}
+
+	return utils.SendSuccess(ctx, res, "timer stopped successfully")
+}
+
+func (c *Controller) AddTimeEntry(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	id := ctx.Params("id")
+	if id == "" {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "id required")
+	}
+
+	var req ManualTimeEntryRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "invalid request body")
+	}
+
+	if err := c.validate.Struct(req); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, utils.ParseValidationError(err))
+	}
+
+	res, err := c.service.AddTimeEntry(userID, id, req)
+	if err != nil {
+		if errors.Is(err, ErrProductNotFound) {
+			return utils.SendError(ctx, fiber.StatusNotFound, err.Error())
+		}
+		return utils.SendError(ctx, fiber.StatusBadRequest, err.Error())
+	}
+
+	return utils.SendCreated(ctx, res, "time entry created successfully")
+}
+
+func (c *Controller) DeleteTimeEntry(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	id := ctx.Params("id")
+	entryID := ctx.Params("entry_id")
+	if id == "" || entryID == "" {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "product id and entry id required")
+	}
+
+	if err := c.service.DeleteTimeEntry(userID, id, entryID); err != nil {
+		if errors.Is(err, ErrProductNotFound) || errors.Is(err, ErrTimeEntryNotFound) {
+			return utils.SendError(ctx, fiber.StatusNotFound, err.Error())
+		}
+		return utils.SendError(ctx, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(ctx, nil, "time entry deleted successfully")
+}
+
+func (c *Controller) AddFitting(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	id := ctx.Params("id")
+	if id == "" {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "id required")
+	}
+
+	var req CreateFittingRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "invalid request body")
+	}
+
+	if err := c.validate.Struct(req); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, utils.ParseValidationError(err))
+	}
+
+	res, err := c.service.AddFitting(userID, id, req)
+	if err != nil {
+		if errors.Is(err, ErrProductNotFound) {
+			return utils.SendError(ctx, fiber.StatusNotFound, err.Error())
+		}
+		return utils.SendError(ctx, fiber.StatusBadRequest, err.Error())
+	}
+
+	return utils.SendCreated(ctx, res, "fitting scheduled successfully")
+}
+
+func (c *Controller) DeleteFitting(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	id := ctx.Params("id")
+	fittingID := ctx.Params("fitting_id")
+	if id == "" || fittingID == "" {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "product id and fitting id required")
+	}
+
+	if err := c.service.DeleteFitting(userID, id, fittingID); err != nil {
+		if errors.Is(err, ErrProductNotFound) || errors.Is(err, ErrFittingNotFound) {
+			return utils.SendError(ctx, fiber.StatusNotFound, err.Error())
+		}
+		return utils.SendError(ctx, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(ctx, nil, "fitting deleted successfully")
+}
+
+func (c *Controller) GetPayments(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	id := ctx.Params("id")
+	if id == "" {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "id required")
+	}
+
+	res, err := c.service.GetPayments(userID, id)
+	if err != nil {
+		if errors.Is(err, ErrProductNotFound) {
+			return utils.SendError(ctx, fiber.StatusNotFound, err.Error())
+		}
+		return utils.SendError(ctx, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(ctx, res, "payments retrieved successfully")
+}
+
+func (c *Controller) AddPayment(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	id := ctx.Params("id")
+	if id == "" {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "id required")
+	}
+
+	var req CreatePaymentRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "invalid request body")
+	}
+
+	if err := c.validate.Struct(req); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, utils.ParseValidationError(err))
+	}
+
+	res, err := c.service.AddPayment(userID, id, req)
+	if err != nil {
+		if errors.Is(err, ErrProductNotFound) {
+			return utils.SendError(ctx, fiber.StatusNotFound, err.Error())
+		}
+		return utils.SendError(ctx, fiber.StatusBadRequest, err.Error())
+	}
+
+	return utils.SendCreated(ctx, res, "payment recorded successfully")
+}
+
+func (c *Controller) DeletePayment(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	id := ctx.Params("id")
+	paymentID := ctx.Params("payment_id")
+	if id == "" || paymentID == "" {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "product id and payment id required")
+	}
+
+	if err := c.service.DeletePayment(userID, id, paymentID); err != nil {
+		if errors.Is(err, ErrProductNotFound) || errors.Is(err, ErrPaymentNotFound) {
+			return utils.SendError(ctx, fiber.StatusNotFound, err.Error())
+		}
+		if errors.Is(err, ErrInvalidTransition) {
+			return utils.SendError(ctx, fiber.StatusConflict, err.Error())
+		}
+		return utils.SendError(ctx, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(ctx, nil, "payment deleted successfully")
+}
+
+func (c *Controller) CreateDocument(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	id := ctx.Params("id")
+	if id == "" {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "id required")
+	}
+
+	var req CreateDocumentRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "invalid request body")
+	}
+
+	if err := c.validate.Struct(req); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, utils.ParseValidationError(err))
+	}
+
+	res, err := c.service.CreateDocument(userID, id, req)
+	if err != nil {
+		if errors.Is(err, ErrProductNotFound) {
+			return utils.SendError(ctx, fiber.StatusNotFound, err.Error())
+		}
+		return utils.SendError(ctx, fiber.StatusBadRequest, err.Error())
+	}
+
+	return utils.SendCreated(ctx, res, "document created successfully")
+}
+
+func (c *Controller) GetDocuments(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	id := ctx.Params("id")
+	if id == "" {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "id required")
+	}
+
+	res, err := c.service.GetDocuments(userID, id)
+	if err != nil {
+		if errors.Is(err, ErrProductNotFound) {
+			return utils.SendError(ctx, fiber.StatusNotFound, err.Error())
+		}
+		return utils.SendError(ctx, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(ctx, res, "documents retrieved successfully")
+}
+
+func (c *Controller) DownloadDocument(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	id := ctx.Params("id")
+	documentID := ctx.Params("document_id")
+	if id == "" || documentID == "" {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "product id and document id required")
+	}
+
+	doc, err := c.service.GetDocument(userID, id, documentID)
+	if err != nil {
+		if errors.Is(err, ErrProductNotFound) || errors.Is(err, ErrDocumentNotFound) {
+			return utils.SendError(ctx, fiber.StatusNotFound, err.Error())
+		}
+		return utils.SendError(ctx, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return ctx.Download(doc.Path, doc.Code()+".pdf")
+}
+
+func (c *Controller) CreateShareLink(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	id := ctx.Params("id")
+	if id == "" {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "id required")
+	}
+
+	var req CreateShareLinkRequest
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(&req); err != nil {
+			return utils.SendError(ctx, fiber.StatusBadRequest, "invalid request body")
+		}
+	}
+
+	if err := c.validate.Struct(req); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, utils.ParseValidationError(err))
+	}
+
+	res, err := c.service.CreateShareLink(userID, id, req)
+	if err != nil {
+		if errors.Is(err, ErrProductNotFound) {
+			return utils.SendError(ctx, fiber.StatusNotFound, err.Error())
+		}
+		return utils.SendError(ctx, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendCreated(ctx, res, "share link created successfully")
+}
+
+func (c *Controller) GetShareLinks(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	id := ctx.Params("id")
+	if id == "" {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "id required")
+	}
+
+	res, err := c.service.GetShareLinks(userID, id)
+	if err != nil {
+		if errors.Is(err, ErrProductNotFound) {
+			return utils.SendError(ctx, fiber.StatusNotFound, err.Error())
+		}
+		return utils.SendError(ctx, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(ctx, res, "share links retrieved successfully")
+}
+
+func (c *Controller) RevokeShareLink(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	id := ctx.Params("id")
+	linkID := ctx.Params("link_id")
+	if id == "" || linkID == "" {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "product id and link id required")
+	}
+
+	if err := c.service.RevokeShareLink(userID, id, linkID); err != nil {
+		if errors.Is(err, ErrProductNotFound) || errors.Is(err, ErrShareLinkNotFound) {
+			return utils.SendError(ctx, fiber.StatusNotFound, err.Error())
+		}
+		return utils.SendError(ctx, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(ctx, nil, "share link revoked successfully")
+}
+
+func (c *Controller) GetPublicOrder(ctx *fiber.Ctx) error {
+	res, err := c.service.GetPublicOrder(ctx.Params("token"))
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusNotFound, "order not found")
+	}
+
+	return utils.SendSuccess(ctx, res, "order retrieved successfully")
+}
+
+// PublicOrderPage renders the order behind a share link as a web page.
+func (c *Controller) PublicOrderPage(ctx *fiber.Ctx) error {
+	res, err := c.service.GetPublicOrder(ctx.Params("token"))
+	if err != nil {
+		return ctx.Status(fiber.StatusNotFound).SendString("This link is no longer available.")
+	}
+
+	ctx.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+	ctx.Set("X-Robots-Tag", "noindex")
+	return publicOrderTemplate.Execute(ctx.Response().BodyWriter(), res)
+}
+
+func (c *Controller) CreateQuoteRevision(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	id := ctx.Params("id")
+	if id == "" {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "id required")
+	}
+
+	var req CreateQuoteRevisionRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "invalid request body")
+	}
+
+	if err := c.validate.Struct(req); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, utils.ParseValidationError(err))
+	}
+
+	res, err := c.service.CreateQuoteRevision(userID, id, req)
+	if err != nil {
+		if errors.Is(err, ErrProductNotFound) {
+			return utils.SendError(ctx, fiber.StatusNotFound, err.Error())
+		}
+		return utils.SendError(ctx, fiber.StatusBadRequest, err.Error())
+	}
+
+	return utils.SendCreated(ctx, res, "quote created successfully")
+}
+
+func (c *Controller) GetQuoteRevisions(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	id := ctx.Params("id")
+	if id == "" {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "id required")
+	}
+
+	res, err := c.service.GetQuoteRevisions(userID, id)
+	if err != nil {
+		if errors.Is(err, ErrProductNotFound) {
+			return utils.SendError(ctx, fiber.StatusNotFound, err.Error())
+		}
+		return utils.SendError(ctx, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(ctx, res, "quotes retrieved successfully")
+}
+
+func (c *Controller) GetPublicQuote(ctx *fiber.Ctx) error {
+	res, err := c.service.GetPublicQuote(ctx.Params("token"))
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusNotFound, "quote not found")
+	}
+
+	return utils.SendSuccess(ctx, res, "quote retrieved successfully")
+}
+
+func (c *Controller) AcceptQuote(ctx *fiber.Ctx) error {
+	res, err := c.service.AcceptQuote(ctx.Params("token"))
+	if err != nil {
+		return sendQuoteError(ctx, err)
+	}
+
+	return utils.SendSuccess(ctx, res, "quote accepted successfully")
+}
+
+func (c *Controller) DeclineQuote(ctx *fiber.Ctx) error {
+	var req DeclineQuoteRequest
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(&req); err != nil {
+			return utils.SendError(ctx, fiber.StatusBadRequest, "invalid request body")
+		}
+	}
+
+	if err := c.validate.Struct(req); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, utils.ParseValidationError(err))
+	}
+
+	res, err := c.service.DeclineQuote(ctx.Params("token"), req)
+	if err != nil {
+		return sendQuoteError(ctx, err)
+	}
+
+	return utils.SendSuccess(ctx, res, "quote declined successfully")
+}
+
+func sendQuoteError(ctx *fiber.Ctx, err error) error {
+	if errors.Is(err, ErrQuoteNotFound) {
+		return utils.SendError(ctx, fiber.StatusNotFound, err.Error())
+	}
+	if errors.Is(err, ErrQuoteClosed) {
+		return utils.SendError(ctx, fiber.StatusConflict, err.Error())
+	}
+	return utils.SendError(ctx, fiber.StatusInternalServerError, err.Error())
+}
+
+// PublicQuotePage renders the quote behind an approval link with buttons to
+// accept or decline it.
+func (c *Controller) PublicQuotePage(ctx *fiber.Ctx) error {
+	res, err := c.service.GetPublicQuote(ctx.Params("token"))
+	return c.renderQuotePage(ctx, res, err, "")
+}
+
+func (c *Controller) AcceptQuotePage(ctx *fiber.Ctx) error {
+	res, err := c.service.AcceptQuote(ctx.Params("token"))
+	return c.renderQuotePage(ctx, res, err, "Thank you, the quote has been accepted.")
+}
+
+func (c *Controller) DeclineQuotePage(ctx *fiber.Ctx) error {
+	var req DeclineQuoteRequest
+	_ = ctx.BodyParser(&req)
+	if reason := []rune(req.Reason); len(reason) > 500 {
+		req.Reason = string(reason[:500])
+	}
+
+	res, err := c.service.DeclineQuote(ctx.Params("token"), req)
+	return c.renderQuotePage(ctx, res, err, "The quote has been declined.")
+}
+
+func (c *Controller) renderQuotePage(ctx *fiber.Ctx, quote *PublicQuoteResponse, err error, message string) error {
+	// A quote already answered is shown as it stands
+	if errors.Is(err, ErrQuoteClosed) {
+		message = "This quote can no longer be answered."
+		quote, err = c.service.GetPublicQuote(ctx.Params("token"))
+	}
+	if err != nil {
+		return ctx.Status(fiber.StatusNotFound).SendString("This link is no longer available.")
+	}
+
+	ctx.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+	ctx.Set("X-Robots-Tag", "noindex")
+	return publicQuoteTemplate.Execute(ctx.Response().BodyWriter(), quotePage{Quote: quote, Message: message})
+}
+
 func getUserIDFromToken(ctx *fiber.Ctx) (string, error) {
 	// Reusing logic from customers/controller ideally, or refactor to utils.
 	// For now, duplicating to keep features decoupled.
@@ -0,0 +1,171 @@
+package products
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TFX0019/api-go-gds/pkg/utils"
+	"github.com/go-pdf/fpdf"
+)
+
+var paymentMethodLabels = map[string]string{
+	"cash":     "Cash",
+	"card":     "Card",
+	"transfer": "Bank transfer",
+	"other":    "Other",
+}
+
+// renderDocument writes the PDF of a quote, invoice or receipt to path.
+func renderDocument(path string, doc Document, data documentData) error {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(18, 18, 18)
+	pdf.SetAutoPageBreak(true, 18)
+	pdf.SetTitle(documentTitles[doc.Type]+" "+doc.Code(), true)
+	pdf.SetAuthor(data.Business.Name, true)
+	pdf.AddPage()
+
+	money := func(v utils.Money) string {
+		return utils.FormatMoney(v, doc.Currency, data.Locale)
+	}
+
+	// Core fonts only cover Windows-1252, which is enough for accented names
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pageWidth, _ := pdf.GetPageSize()
+	left, _, right, _ := pdf.GetMargins()
+	width := pageWidth - left - right
+
+	// Workshop and document details
+	top := pdf.GetY()
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.CellFormat(width/2, 8, tr(data.Business.Name), "", 2, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 9)
+	for _, line := range []string{data.Business.Address, data.Business.Phone, data.Business.Email} {
+		if line != "" {
+			pdf.CellFormat(width/2, 5, tr(line), "", 2, "L", false, 0, "")
+		}
+	}
+	if data.Business.TaxID != "" {
+		pdf.CellFormat(width/2, 5, tr("Tax ID: "+data.Business.TaxID), "", 2, "L", false, 0, "")
+	}
+	bottom := pdf.GetY()
+
+	pdf.SetXY(left+width/2, top)
+	pdf.SetFont("Helvetica", "B", 18)
+	pdf.CellFormat(width/2, 8, strings.ToUpper(documentTitles[doc.Type]), "", 2, "R", false, 0, "")
+	pdf.SetFont("Helvetica", "", 9)
+	pdf.CellFormat(width/2, 5, "No. "+doc.Code(), "", 2, "R", false, 0, "")
+	pdf.CellFormat(width/2, 5, "Date: "+time.Now().Format("2006-01-02"), "", 2, "R", false, 0, "")
+	if due := formatDate(data.Product.DueDate); due != nil && doc.Type != DocumentReceipt {
+		pdf.CellFormat(width/2, 5, "Due date: "+*due, "", 2, "R", false, 0, "")
+	}
+	if pdf.GetY() > bottom {
+		bottom = pdf.GetY()
+	}
+	pdf.SetXY(left, bottom+8)
+
+	// Customer
+	if data.Customer != nil {
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.CellFormat(width, 6, "Bill to", "", 1, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 10)
+		for _, line := range []string{data.Customer.Name, data.Customer.Phone, data.Customer.Email} {
+			if line != "" {
+				pdf.CellFormat(width, 5, tr(line), "", 1, "L", false, 0, "")
+			}
+		}
+		pdf.Ln(4)
+	}
+
+	pdf.SetFont("Helvetica", "B", 11)
+	pdf.CellFormat(width, 7, tr(data.Product.Name), "", 1, "L", false, 0, "")
+	pdf.Ln(2)
+
+	// Line items
+	cols := []float64{width * 0.46, width * 0.16, width * 0.19, width * 0.19}
+	pdf.SetFillColor(240, 238, 235)
+	pdf.SetFont("Helvetica", "B", 9)
+	for i, header := range []string{"Description", "Qty", "Unit price", "Amount"} {
+		align := "R"
+		if i == 0 {
+			align = "L"
+		}
+		pdf.CellFormat(cols[i], 7, header, "B", 0, align, true, 0, "")
+	}
+	pdf.Ln(-1)
+
+	pdf.SetFont("Helvetica", "", 9)
+	for _, line := range data.Lines {
+		quantity := formatQuantity(line.Quantity)
+		if line.Unit != "" {
+			quantity += " " + line.Unit
+		}
+		pdf.CellFormat(cols[0], 6, tr(line.Description), "", 0, "L", false, 0, "")
+		pdf.CellFormat(cols[1], 6, tr(quantity), "", 0, "R", false, 0, "")
+		pdf.CellFormat(cols[2], 6, tr(money(line.UnitPrice)), "", 0, "R", false, 0, "")
+		pdf.CellFormat(cols[3], 6, tr(money(line.Amount)), "", 1, "R", false, 0, "")
+	}
+
+	// Totals
+	labelWidth := cols[0] + cols[1] + cols[2]
+	totalBorder := "T"
+	if len(data.Adjustments) > 0 {
+		pdf.SetFont("Helvetica", "", 10)
+		for _, line := range data.Adjustments {
+			pdf.CellFormat(labelWidth, 7, tr(line.Description), totalBorder, 0, "R", false, 0, "")
+			pdf.CellFormat(cols[3], 7, tr(money(line.Amount)), totalBorder, 1, "R", false, 0, "")
+			totalBorder = ""
+		}
+	}
+	if !doc.TaxAmount.IsZero() {
+		taxLabel := fmt.Sprintf("%s %s%%", data.TaxName, formatQuantity(data.Product.TaxRate))
+		if data.Product.TaxInclusive {
+			taxLabel += " (included)"
+		}
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.CellFormat(labelWidth, 7, "Subtotal", totalBorder, 0, "R", false, 0, "")
+		pdf.CellFormat(cols[3], 7, tr(money(doc.Total.Sub(doc.TaxAmount))), totalBorder, 1, "R", false, 0, "")
+		pdf.CellFormat(labelWidth, 7, tr(taxLabel), "", 0, "R", false, 0, "")
+		pdf.CellFormat(cols[3], 7, tr(money(doc.TaxAmount)), "", 1, "R", false, 0, "")
+		totalBorder = ""
+	}
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.CellFormat(labelWidth, 8, "Total", totalBorder, 0, "R", false, 0, "")
+	pdf.CellFormat(cols[3], 8, tr(money(doc.Total)), totalBorder, 1, "R", false, 0, "")
+
+	if len(data.Payments) > 0 {
+		pdf.Ln(4)
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.CellFormat(width, 6, "Payments received", "", 1, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 9)
+		for _, p := range data.Payments {
+			method := paymentMethodLabels[p.Method]
+			if method == "" {
+				method = p.Method
+			}
+			pdf.CellFormat(cols[0]+cols[1], 5, p.PaidAt.Format("2006-01-02")+"  "+method, "", 0, "L", false, 0, "")
+			pdf.CellFormat(cols[2], 5, "", "", 0, "R", false, 0, "")
+			pdf.CellFormat(cols[3], 5, tr(money(p.Amount)), "", 1, "R", false, 0, "")
+		}
+
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.CellFormat(labelWidth, 7, "Amount paid", "T", 0, "R", false, 0, "")
+		pdf.CellFormat(cols[3], 7, tr(money(doc.AmountPaid)), "T", 1, "R", false, 0, "")
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.CellFormat(labelWidth, 7, "Balance due", "", 0, "R", false, 0, "")
+		pdf.CellFormat(cols[3], 7, tr(money(outstandingBalance(data.Product))), "", 1, "R", false, 0, "")
+	}
+
+	if data.Notes != "" {
+		pdf.Ln(6)
+		pdf.SetFont("Helvetica", "", 9)
+		pdf.MultiCell(width, 5, tr(data.Notes), "", "L", false)
+	}
+
+	return pdf.OutputFileAndClose(path)
+}
+
+func formatQuantity(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
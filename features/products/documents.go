@@ -0,0 +1,281 @@
+package products
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/TFX0019/api-go-gds/features/customers"
+	"github.com/TFX0019/api-go-gds/pkg/utils"
+	"github.com/google/uuid"
+)
+
+var ErrDocumentNotFound = errors.New("document not found")
+
+const (
+	DocumentQuote   = "quote"
+	DocumentInvoice = "invoice"
+	DocumentReceipt = "receipt"
+)
+
+var documentPrefixes = map[string]string{
+	DocumentQuote:   "QUO",
+	DocumentInvoice: "INV",
+	DocumentReceipt: "REC",
+}
+
+var documentTitles = map[string]string{
+	DocumentQuote:   "Quote",
+	DocumentInvoice: "Invoice",
+	DocumentReceipt: "Receipt",
+}
+
+const documentsDir = "uploads/documents"
+
+// documentLine is a line item as the customer sees it, with the profit
+// already spread over the price.
+type documentLine struct {
+	Description string
+	Quantity    float64
+	Unit        string
+	UnitPrice   utils.Money
+	Amount      utils.Money
+}
+
+type documentBusiness struct {
+	Name    string
+	Address string
+	Phone   string
+	Email   string
+	TaxID   string
+}
+
+type documentData struct {
+	Business documentBusiness
+	Customer *customers.Customer
+	Product  Product
+	Lines    []documentLine
+	// Discounts and surcharges, printed between the lines and the tax
+	Adjustments []documentLine
+	Payments    []Payment
+	Notes       string
+	Locale      string
+	TaxName     string
+}
+
+func (s *service) CreateDocument(userID, productID string, req CreateDocumentRequest) (*DocumentResponse, error) {
+	product, err := s.findOwned(userID, productID)
+	if err != nil {
+		return nil, err
+	}
+	if product.Status == StatusCancelled {
+		return nil, errors.New("cannot issue documents for a cancelled order")
+	}
+	if req.Type == DocumentReceipt && len(product.Payments) == 0 {
+		return nil, errors.New("the order has no payments to issue a receipt for")
+	}
+
+	user, err := s.authRepo.FindByID(product.UserID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	data := documentData{
+		Business: documentBusiness{
+			Name:    user.BusinessName,
+			Address: user.BusinessAddress,
+			Phone:   user.BusinessPhone,
+			Email:   user.BusinessEmail,
+			TaxID:   user.BusinessTaxID,
+		},
+		Product:     *product,
+		Lines:       documentLines(*product),
+		Adjustments: adjustmentLines(*product),
+		Payments:    product.Payments,
+		Notes:       req.Notes,
+		Locale:      user.Locale,
+		TaxName:     "Tax",
+	}
+	if data.Business.Name == "" {
+		data.Business.Name = user.Name
+	}
+	if data.Business.Email == "" {
+		data.Business.Email = user.Email
+	}
+	if product.ClientID != nil {
+		if customer, err := s.customersRepo.FindByID(product.ClientID.String()); err == nil {
+			data.Customer = customer
+		}
+	}
+	// The profile's registration number takes precedence over the business one
+	if product.TaxProfileID != nil {
+		if profile, err := s.taxProfilesRepo.FindByID(product.TaxProfileID.String()); err == nil {
+			data.TaxName = profile.Name
+			if profile.TaxID != "" {
+				data.Business.TaxID = profile.TaxID
+			}
+		}
+	}
+
+	doc := &Document{
+		ID:              uuid.New(),
+		ProductID:       product.ID,
+		UserID:          product.UserID,
+		Type:            req.Type,
+		Total:           product.Total,
+		DiscountAmount:  product.DiscountAmount,
+		SurchargeAmount: product.SurchargeAmount,
+		TaxAmount:       product.TaxAmount,
+		AmountPaid:      amountPaid(*product),
+		Currency:        product.Currency,
+	}
+	err = s.repo.CreateDocument(doc, func(doc *Document) error {
+		dir := filepath.Join(documentsDir, fmt.Sprintf("%d", doc.UserID))
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+		doc.Path = filepath.ToSlash(filepath.Join(dir, doc.ID.String()+".pdf"))
+		return renderDocument(doc.Path, *doc, data)
+	})
+	if err != nil {
+		// The number was rolled back, so the file written for it goes too
+		if doc.Path != "" {
+			os.Remove(doc.Path)
+		}
+		return nil, err
+	}
+
+	res := mapDocumentToResponse(*doc)
+	return &res, nil
+}
+
+func (s *service) GetDocuments(userID, productID string) ([]DocumentResponse, error) {
+	if _, err := s.findOwned(userID, productID); err != nil {
+		return nil, err
+	}
+
+	docs, err := s.repo.FindDocuments(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]DocumentResponse, 0, len(docs))
+	for _, d := range docs {
+		res = append(res, mapDocumentToResponse(d))
+	}
+	return res, nil
+}
+
+// GetDocument returns a stored document of the product so it can be
+// downloaded again.
+func (s *service) GetDocument(userID, productID, documentID string) (*Document, error) {
+	if _, err := s.findOwned(userID, productID); err != nil {
+		return nil, err
+	}
+
+	doc, err := s.repo.FindDocumentByID(documentID)
+	if err != nil || doc.ProductID.String() != productID {
+		return nil, ErrDocumentNotFound
+	}
+	if _, err := os.Stat(doc.Path); err != nil {
+		return nil, ErrDocumentNotFound
+	}
+	return doc, nil
+}
+
+// documentLines breaks the order down into materials, labor and workshop
+// expenses. Each line is marked up by the order's profit percentage so the
+// profit is not shown, and the last line absorbs the rounding so the lines
+// add up to the total before adjustments and tax.
+func documentLines(p Product) []documentLine {
+	net := p.netTotal().Add(p.DiscountAmount).Sub(p.SurchargeAmount)
+	markup := func(v utils.Money) utils.Money {
+		if p.BaseTotal.IsPositive() {
+			return v.Scale(net, p.BaseTotal)
+		}
+		return v
+	}
+
+	var lines []documentLine
+	if len(p.Materials) > 0 {
+		for _, m := range p.Materials {
+			lines = append(lines, documentLine{
+				Description: m.Name,
+				Quantity:    m.Quantity,
+				Unit:        m.Unit,
+				UnitPrice:   markup(m.UnitPrice).Round(),
+				Amount:      markup(m.UnitPrice.Mul(m.Quantity)).Round(),
+			})
+		}
+	} else if p.MaterialsCost.IsPositive() {
+		lines = append(lines, fixedLine("Materials", markup(p.MaterialsCost)))
+	}
+
+	if p.HoursCost.IsPositive() {
+		hours := p.LoggedHours
+		if hours == 0 {
+			hours = p.EstimatedHours
+		}
+		if hours > 0 {
+			amount := markup(p.HoursCost).Round()
+			lines = append(lines, documentLine{
+				Description: "Labor",
+				Quantity:    hours,
+				Unit:        "h",
+				UnitPrice:   amount.Div(hours).Round(),
+				Amount:      amount,
+			})
+		} else {
+			lines = append(lines, fixedLine("Labor", markup(p.HoursCost)))
+		}
+	}
+
+	if p.FixedExpensesAmount.IsPositive() {
+		lines = append(lines, fixedLine("Workshop expenses", markup(p.FixedExpensesAmount)))
+	}
+
+	if len(lines) == 0 {
+		return []documentLine{fixedLine(p.Name, net)}
+	}
+
+	var sum utils.Money
+	for _, l := range lines {
+		sum = sum.Add(l.Amount)
+	}
+	if diff := net.Sub(sum); !diff.IsZero() {
+		last := &lines[len(lines)-1]
+		last.Amount = last.Amount.Add(diff)
+		if last.Quantity == 1 {
+			last.UnitPrice = last.Amount
+		}
+	}
+	return lines
+}
+
+func fixedLine(description string, amount utils.Money) documentLine {
+	amount = amount.Round()
+	return documentLine{
+		Description: description,
+		Quantity:    1,
+		UnitPrice:   amount,
+		Amount:      amount,
+	}
+}
+
+func mapDocumentToResponse(d Document) DocumentResponse {
+	return DocumentResponse{
+		ID:              d.ID.String(),
+		ProductID:       d.ProductID.String(),
+		Type:            d.Type,
+		Number:          d.Code(),
+		DiscountAmount:  d.DiscountAmount,
+		SurchargeAmount: d.SurchargeAmount,
+		TaxAmount:       d.TaxAmount,
+		Total:           d.Total,
+		AmountPaid:      d.AmountPaid,
+		Currency:        d.Currency,
+		DownloadURL:     publicURL(fmt.Sprintf("api/products/%s/documents/%s/download", d.ProductID, d.ID)),
+		CreatedAt:       d.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
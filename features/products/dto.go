@@ -1,37 +1,143 @@
 package products
 
+import "github.com/TFX0019/api-go-gds/pkg/utils"
+
 type CreateProductRequest struct {
-	Name                 string  `json:"name" validate:"required"`
-	ClientID             *string `json:"client_id" validate:"omitempty,uuid"`
-	MaterialsCost        float64 `json:"materials_cost" validate:"gte=0"`
-	HoursCost            float64 `json:"hours_cost" validate:"gte=0"`
-	ProfitPercentage     float64 `json:"profit_percentage" validate:"gte=0"`
-	IncludeFixedExpenses bool    `json:"include_fixed_expenses"`
-	FixedExpenseRate     float64 `json:"fixed_expense_rate" validate:"gte=0"`
-	Subtotal             float64 `json:"subtotal" validate:"gte=0"`
-	FixedExpensesAmount  float64 `json:"fixed_expenses_amount" validate:"gte=0"`
-	BaseTotal            float64 `json:"base_total" validate:"gte=0"`
-	ProfitAmount         float64 `json:"profit_amount" validate:"gte=0"`
-	Total                float64 `json:"total" validate:"gte=0"`
+	Name                  string  `json:"name" validate:"required"`
+	ClientID              *string `json:"client_id" validate:"omitempty,uuid"`
+	MeasurementSnapshotID *string `json:"measurement_snapshot_id" validate:"omitempty,uuid"`
+	DueDate               *string `json:"due_date" validate:"omitempty,datetime=2006-01-02"`
+	// Bill of materials; when given, materials_cost is derived from it
+	Materials            []ProductMaterialRequest `json:"materials" validate:"omitempty,dive"`
+	MaterialsCost        utils.Money              `json:"materials_cost" validate:"gte=0"`
+	HoursCost            utils.Money              `json:"hours_cost" validate:"gte=0"`
+	EstimatedHours       float64                  `json:"estimated_hours" validate:"gte=0"`
+	ProfitPercentage     float64                  `json:"profit_percentage" validate:"gte=0"`
+	IncludeFixedExpenses bool                     `json:"include_fixed_expenses"`
+	FixedExpenseRate     float64                  `json:"fixed_expense_rate" validate:"gte=0"`
+	// Discounts and surcharges applied after the profit
+	Adjustments []AdjustmentRequest `json:"adjustments" validate:"omitempty,dive"`
+	// Tax profile to charge; the default profile when omitted, none when empty
+	TaxProfileID *string `json:"tax_profile_id" validate:"omitempty,uuid"`
+	// Derived amounts are computed by the server; when sent they must match
+	Subtotal            *utils.Money `json:"subtotal" validate:"omitempty,gte=0"`
+	FixedExpensesAmount *utils.Money `json:"fixed_expenses_amount" validate:"omitempty,gte=0"`
+	BaseTotal           *utils.Money `json:"base_total" validate:"omitempty,gte=0"`
+	ProfitAmount        *utils.Money `json:"profit_amount" validate:"omitempty,gte=0"`
+	DiscountAmount      *utils.Money `json:"discount_amount" validate:"omitempty,gte=0"`
+	SurchargeAmount     *utils.Money `json:"surcharge_amount" validate:"omitempty,gte=0"`
+	TaxAmount           *utils.Money `json:"tax_amount" validate:"omitempty,gte=0"`
+	Total               *utils.Money `json:"total" validate:"omitempty,gte=0"`
+	// Reference images already saved under uploads, e.g. from a template
+	Images []string `json:"-"`
 }
 
 type UpdateProductRequest struct {
-	Name                 string  `json:"name"`
-	ClientID             *string `json:"client_id" validate:"omitempty,uuid"`
-	MaterialsCost        float64 `json:"materials_cost" validate:"gte=0"`
-	HoursCost            float64 `json:"hours_cost" validate:"gte=0"`
-	ProfitPercentage     float64 `json:"profit_percentage" validate:"gte=0"`
-	IncludeFixedExpenses bool    `json:"include_fixed_expenses"`
-	FixedExpenseRate     float64 `json:"fixed_expense_rate" validate:"gte=0"`
-	Subtotal             float64 `json:"subtotal" validate:"gte=0"`
-	FixedExpensesAmount  float64 `json:"fixed_expenses_amount" validate:"gte=0"`
-	BaseTotal            float64 `json:"base_total" validate:"gte=0"`
-	ProfitAmount         float64 `json:"profit_amount" validate:"gte=0"`
-	Total                float64 `json:"total" validate:"gte=0"`
+	Name                  string  `json:"name"`
+	ClientID              *string `json:"client_id" validate:"omitempty,uuid"`
+	MeasurementSnapshotID *string `json:"measurement_snapshot_id" validate:"omitempty,uuid"`
+	DueDate               *string `json:"due_date" validate:"omitempty,datetime=2006-01-02"`
+	// Replaces the bill of materials when sent, an empty list removes it
+	Materials            *[]ProductMaterialRequest `json:"materials" validate:"omitempty,dive"`
+	MaterialsCost        utils.Money               `json:"materials_cost" validate:"gte=0"`
+	HoursCost            utils.Money               `json:"hours_cost" validate:"gte=0"`
+	EstimatedHours       *float64                  `json:"estimated_hours" validate:"omitempty,gte=0"`
+	ProfitPercentage     float64                   `json:"profit_percentage" validate:"gte=0"`
+	IncludeFixedExpenses bool                      `json:"include_fixed_expenses"`
+	FixedExpenseRate     float64                   `json:"fixed_expense_rate" validate:"gte=0"`
+	// Replaces the adjustments when sent, an empty list removes them
+	Adjustments *[]AdjustmentRequest `json:"adjustments" validate:"omitempty,dive"`
+	// Replaces the tax profile when sent, an empty id removes the tax
+	TaxProfileID *string `json:"tax_profile_id" validate:"omitempty,uuid"`
+	// Derived amounts are computed by the server; when sent they must match
+	Subtotal            *utils.Money `json:"subtotal" validate:"omitempty,gte=0"`
+	FixedExpensesAmount *utils.Money `json:"fixed_expenses_amount" validate:"omitempty,gte=0"`
+	BaseTotal           *utils.Money `json:"base_total" validate:"omitempty,gte=0"`
+	ProfitAmount        *utils.Money `json:"profit_amount" validate:"omitempty,gte=0"`
+	DiscountAmount      *utils.Money `json:"discount_amount" validate:"omitempty,gte=0"`
+	SurchargeAmount     *utils.Money `json:"surcharge_amount" validate:"omitempty,gte=0"`
+	TaxAmount           *utils.Money `json:"tax_amount" validate:"omitempty,gte=0"`
+	Total               *utils.Money `json:"total" validate:"omitempty,gte=0"`
+}
+
+// AdjustmentRequest is a discount or surcharge. Percentages are taken from
+// the order's price with profit, before any other adjustment.
+type AdjustmentRequest struct {
+	Kind   string  `json:"kind" validate:"required,oneof=discount surcharge"`
+	Type   string  `json:"type" validate:"required,oneof=percentage fixed"`
+	Value  float64 `json:"value" validate:"gt=0"`
+	Reason string  `json:"reason" validate:"required,max=120"`
+}
+
+type AdjustmentResponse struct {
+	ID     string      `json:"id"`
+	Kind   string      `json:"kind"`
+	Type   string      `json:"type"`
+	Value  float64     `json:"value"`
+	Reason string      `json:"reason"`
+	Amount utils.Money `json:"amount"`
+}
+
+type ProductMaterialRequest struct {
+	MaterialID string  `json:"material_id" validate:"required,uuid"`
+	Quantity   float64 `json:"quantity" validate:"gt=0"`
+}
+
+type ProductMaterialResponse struct {
+	ID         string      `json:"id"`
+	MaterialID *string     `json:"material_id"`
+	Name       string      `json:"name"`
+	Unit       string      `json:"unit"`
+	UnitPrice  utils.Money `json:"unit_price"`
+	Quantity   float64     `json:"quantity"`
+	Cost       utils.Money `json:"cost"`
+}
+
+// StockWarningResponse reports a material without enough stock for an order.
+type StockWarningResponse struct {
+	MaterialID string  `json:"material_id"`
+	Name       string  `json:"name"`
+	Unit       string  `json:"unit"`
+	Required   float64 `json:"required"`
+	Available  float64 `json:"available"`
+}
+
+type PricingRequest struct {
+	MaterialsCost        utils.Money         `json:"materials_cost" validate:"gte=0"`
+	HoursCost            utils.Money         `json:"hours_cost" validate:"gte=0"`
+	ProfitPercentage     float64             `json:"profit_percentage" validate:"gte=0"`
+	IncludeFixedExpenses bool                `json:"include_fixed_expenses"`
+	FixedExpenseRate     float64             `json:"fixed_expense_rate" validate:"gte=0"`
+	Adjustments          []AdjustmentRequest `json:"adjustments" validate:"omitempty,dive"`
+	TaxRate              float64             `json:"tax_rate" validate:"gte=0,lte=100"`
+	TaxInclusive         bool                `json:"tax_inclusive"`
+}
+
+type PriceBreakdownResponse struct {
+	MaterialsCost       utils.Money `json:"materials_cost"`
+	HoursCost           utils.Money `json:"hours_cost"`
+	Subtotal            utils.Money `json:"subtotal"`
+	FixedExpensesAmount utils.Money `json:"fixed_expenses_amount"`
+	BaseTotal           utils.Money `json:"base_total"`
+	ProfitAmount        utils.Money `json:"profit_amount"`
+	DiscountAmount      utils.Money `json:"discount_amount"`
+	SurchargeAmount     utils.Money `json:"surcharge_amount"`
+	TaxAmount           utils.Money `json:"tax_amount"`
+	Total               utils.Money `json:"total"`
 }
 
 type UpdateProductStatusRequest struct {
-	Status string `json:"status" validate:"required,oneof=pending in_process complete paid"`
+	Status string `json:"status" validate:"required,oneof=pending in_process complete delivered paid cancelled"`
+	Note   string `json:"note"`
+}
+
+type StatusHistoryResponse struct {
+	ID         string `json:"id"`
+	FromStatus string `json:"from_status"`
+	ToStatus   string `json:"to_status"`
+	ChangedBy  uint   `json:"changed_by"`
+	Note       string `json:"note"`
+	CreatedAt  string `json:"created_at"`
 }
 
 type PaginationQuery struct {
@@ -47,25 +153,45 @@ type ProductImageResponse struct {
 }
 
 type ProductResponse struct {
-	ID                   string                 `json:"id"`
-	UserID               string                 `json:"user_id"`
-	Name                 string                 `json:"name"`
-	ClientID             *string                `json:"client_id,omitempty"`
-	MaterialsCost        float64                `json:"materials_cost"`
-	HoursCost            float64                `json:"hours_cost"`
-	ProfitPercentage     float64                `json:"profit_percentage"`
-	IncludeFixedExpenses bool                   `json:"include_fixed_expenses"`
-	FixedExpenseRate     float64                `json:"fixed_expense_rate"`
-	Subtotal             float64                `json:"subtotal"`
-	FixedExpensesAmount  float64                `json:"fixed_expenses_amount"`
-	BaseTotal            float64                `json:"base_total"`
-	ProfitAmount         float64                `json:"profit_amount"`
-	Total                float64                `json:"total"`
-	Status               string                 `json:"status"`
-	Images               []ProductImageResponse `json:"images"`
-	DatePaid             *string                `json:"date_paid"`
-	CreatedAt            string                 `json:"created_at"`
-	UpdatedAt            string                 `json:"updated_at"`
+	ID                    string                    `json:"id"`
+	UserID                string                    `json:"user_id"`
+	Name                  string                    `json:"name"`
+	ClientID              *string                   `json:"client_id,omitempty"`
+	MeasurementSnapshotID *string                   `json:"measurement_snapshot_id,omitempty"`
+	MaterialsCost         utils.Money               `json:"materials_cost"`
+	HoursCost             utils.Money               `json:"hours_cost"`
+	EstimatedHours        float64                   `json:"estimated_hours"`
+	LoggedHours           float64                   `json:"logged_hours"`
+	ProfitPercentage      float64                   `json:"profit_percentage"`
+	IncludeFixedExpenses  bool                      `json:"include_fixed_expenses"`
+	FixedExpenseRate      float64                   `json:"fixed_expense_rate"`
+	Subtotal              utils.Money               `json:"subtotal"`
+	FixedExpensesAmount   utils.Money               `json:"fixed_expenses_amount"`
+	BaseTotal             utils.Money               `json:"base_total"`
+	ProfitAmount          utils.Money               `json:"profit_amount"`
+	Adjustments           []AdjustmentResponse      `json:"adjustments"`
+	DiscountAmount        utils.Money               `json:"discount_amount"`
+	SurchargeAmount       utils.Money               `json:"surcharge_amount"`
+	TaxProfileID          *string                   `json:"tax_profile_id"`
+	TaxRate               float64                   `json:"tax_rate"`
+	TaxInclusive          bool                      `json:"tax_inclusive"`
+	TaxAmount             utils.Money               `json:"tax_amount"`
+	Total                 utils.Money               `json:"total"`
+	Currency              string                    `json:"currency"`
+	Status                string                    `json:"status"`
+	AmountPaid            utils.Money               `json:"amount_paid"`
+	OutstandingBalance    utils.Money               `json:"outstanding_balance"`
+	Images                []ProductImageResponse    `json:"images"`
+	Materials             []ProductMaterialResponse `json:"materials"`
+	StockConsumed         bool                      `json:"stock_consumed"`
+	StockWarnings         []StockWarningResponse    `json:"stock_warnings,omitempty"`
+	DatePaid              *string                   `json:"date_paid"`
+	DueDate               *string                   `json:"due_date"`
+	Fittings              []FittingResponse         `json:"fittings"`
+	AcceptedQuoteID       *string                   `json:"accepted_quote_id"`
+	QuoteAcceptedAt       *string                   `json:"quote_accepted_at"`
+	CreatedAt             string                    `json:"created_at"`
+	UpdatedAt             string                    `json:"updated_at"`
 }
 
 type PaginatedResponse struct {
@@ -75,9 +201,285 @@ type PaginatedResponse struct {
 	Limit int               `json:"limit"`
 }
 
+// DueOrdersQuery pages through the orders due soon or already late.
+type DueOrdersQuery struct {
+	Page  int `query:"page"`
+	Limit int `query:"limit"`
+	Days  int `query:"days" validate:"omitempty,min=1,max=365"` // Upcoming window, 7 days by default
+}
+
+type CreateFittingRequest struct {
+	ScheduledAt string `json:"scheduled_at" validate:"required,datetime=2006-01-02T15:04:05Z07:00"`
+	Note        string `json:"note" validate:"max=255"`
+}
+
+type FittingResponse struct {
+	ID          string `json:"id"`
+	ProductID   string `json:"product_id"`
+	ScheduledAt string `json:"scheduled_at"`
+	Note        string `json:"note"`
+	CreatedAt   string `json:"created_at"`
+}
+
+type CreatePaymentRequest struct {
+	Amount utils.Money `json:"amount" validate:"required,gt=0"`
+	Method string      `json:"method" validate:"omitempty,oneof=cash card transfer other"`
+	PaidAt *string     `json:"paid_at" validate:"omitempty,datetime=2006-01-02"`
+	Note   string      `json:"note"`
+}
+
+type PaymentResponse struct {
+	ID        string      `json:"id"`
+	ProductID string      `json:"product_id"`
+	Amount    utils.Money `json:"amount"`
+	Method    string      `json:"method"`
+	PaidAt    string      `json:"paid_at"`
+	Note      string      `json:"note"`
+	CreatedAt string      `json:"created_at"`
+}
+
+type PaymentsResponse struct {
+	Currency           string            `json:"currency"`
+	Total              utils.Money       `json:"total"`
+	AmountPaid         utils.Money       `json:"amount_paid"`
+	OutstandingBalance utils.Money       `json:"outstanding_balance"`
+	Payments           []PaymentResponse `json:"payments"`
+}
+
+// ProfitLossQuery selects the report period: a from/to range, or a month
+// and year. Without any of them the report covers all time.
+type ProfitLossQuery struct {
+	Month    string `query:"month"`                                         // e.g. jan or 1, of year
+	Year     int    `query:"year" validate:"omitempty,min=2000,max=2100"`   // Defaults to the current year
+	From     string `query:"from" validate:"omitempty,datetime=2006-01-02"` // Defaults to January 1st of to's year
+	To       string `query:"to" validate:"omitempty,datetime=2006-01-02"`   // Defaults to today
+	GroupBy  string `query:"group_by" validate:"omitempty,oneof=month week customer"`
+	Compare  bool   `query:"compare"`                                    // Adds the previous period of the same length
+	Currency string `query:"currency" validate:"omitempty,len=3"`        // Defaults to the user's currency
+	Format   string `query:"format" validate:"omitempty,oneof=csv xlsx"` // Export only, csv by default
+}
+
+// ProfitLossAmounts are the payments received in a period and their share
+// of each order's costs, profit, adjustments and tax: received = materials +
+// hours + fixed expenses + profit - discounts + surcharges + tax.
+type ProfitLossAmounts struct {
+	OrderCount    int64       `json:"order_count"` // Orders with payments in the period
+	TotalReceived utils.Money `json:"total_received"`
+	// Payments received less the tax collected for the tax authority
+	TotalRevenue             utils.Money `json:"total_revenue"`
+	TotalMaterialsCost       utils.Money `json:"total_materials_cost"`
+	TotalHoursCost           utils.Money `json:"total_hours_cost"`
+	TotalFixedExpensesAmount utils.Money `json:"total_fixed_expenses_amount"`
+	TotalProfitAmount        utils.Money `json:"total_profit_amount"`
+	TotalDiscountAmount      utils.Money `json:"total_discount_amount"`
+	TotalSurchargeAmount     utils.Money `json:"total_surcharge_amount"`
+	TotalTaxAmount           utils.Money `json:"total_tax_amount"`
+}
+
 type ProfitLossResponse struct {
-	TotalMaterialsCost       float64 `json:"total_materials_cost"`
-	TotalHoursCost           float64 `json:"total_hours_cost"`
-	TotalFixedExpensesAmount float64 `json:"total_fixed_expenses_amount"`
-	TotalProfitAmount        float64 `json:"total_profit_amount"`
+	Currency string  `json:"currency"`
+	From     *string `json:"from"` // Nil for all time
+	To       *string `json:"to"`
+	GroupBy  string  `json:"group_by,omitempty"`
+	ProfitLossAmounts
+	Groups   []ProfitLossGroupResponse `json:"groups,omitempty"`
+	Previous *ProfitLossPreviousPeriod `json:"previous,omitempty"`
+}
+
+type ProfitLossGroupResponse struct {
+	Key   string `json:"key"` // YYYY-MM, the Monday of the week or the customer id
+	Label string `json:"label"`
+	ProfitLossAmounts
+}
+
+// ProfitLossPreviousPeriod is the period of the same length just before the
+// report's, with the change of the main amounts in percent. A change is nil
+// when the previous amount is zero.
+type ProfitLossPreviousPeriod struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	ProfitLossAmounts
+	ReceivedChange *float64 `json:"received_change"`
+	RevenueChange  *float64 `json:"revenue_change"`
+	ProfitChange   *float64 `json:"profit_change"`
+}
+
+type TaxReportQuery struct {
+	From     string `query:"from" validate:"omitempty,datetime=2006-01-02"` // Defaults to January 1st
+	To       string `query:"to" validate:"omitempty,datetime=2006-01-02"`   // Defaults to today
+	Currency string `query:"currency" validate:"omitempty,len=3"`           // Defaults to the user's currency
+}
+
+type TaxReportPeriodResponse struct {
+	Period        string      `json:"period"` // YYYY-MM
+	TotalReceived utils.Money `json:"total_received"`
+	TaxCollected  utils.Money `json:"tax_collected"`
+	NetAmount     utils.Money `json:"net_amount"`
+}
+
+// TaxReportResponse reports the tax collected through the payments received
+// in each month of the period.
+type TaxReportResponse struct {
+	Currency      string                    `json:"currency"`
+	From          string                    `json:"from"`
+	To            string                    `json:"to"`
+	Periods       []TaxReportPeriodResponse `json:"periods"`
+	TotalReceived utils.Money               `json:"total_received"`
+	TaxCollected  utils.Money               `json:"tax_collected"`
+	NetAmount     utils.Money               `json:"net_amount"`
+}
+
+type CreateDocumentRequest struct {
+	Type  string `json:"type" validate:"required,oneof=quote invoice receipt"`
+	Notes string `json:"notes" validate:"omitempty,max=1000"`
+}
+
+type DocumentResponse struct {
+	ID              string      `json:"id"`
+	ProductID       string      `json:"product_id"`
+	Type            string      `json:"type"`
+	Number          string      `json:"number"`
+	DiscountAmount  utils.Money `json:"discount_amount"`
+	SurchargeAmount utils.Money `json:"surcharge_amount"`
+	TaxAmount       utils.Money `json:"tax_amount"`
+	Total           utils.Money `json:"total"`
+	AmountPaid      utils.Money `json:"amount_paid"`
+	Currency        string      `json:"currency"`
+	DownloadURL     string      `json:"download_url"`
+	CreatedAt       string      `json:"created_at"`
+}
+
+type CreateShareLinkRequest struct {
+	// Days until the link expires, 0 for a link that never expires
+	ExpiresInDays int `json:"expires_in_days" validate:"gte=0"`
+}
+
+type ShareLinkResponse struct {
+	ID        string  `json:"id"`
+	ProductID string  `json:"product_id"`
+	Token     string  `json:"token"`
+	URL       string  `json:"url"`
+	Active    bool    `json:"active"`
+	ExpiresAt *string `json:"expires_at"`
+	RevokedAt *string `json:"revoked_at"`
+	CreatedAt string  `json:"created_at"`
+}
+
+type WorkshopResponse struct {
+	Name      string `json:"name"`
+	AvatarURL string `json:"avatar_url,omitempty"`
+}
+
+// PublicOrderResponse is the only order data shown through a share link.
+type PublicOrderResponse struct {
+	Name               string           `json:"name"`
+	Status             string           `json:"status"`
+	DueDate            *string          `json:"due_date"`
+	Images             []string         `json:"images"`
+	Total              utils.Money      `json:"total"`
+	OutstandingBalance utils.Money      `json:"outstanding_balance"`
+	Currency           string           `json:"currency"`
+	Locale             string           `json:"-"`
+	Workshop           WorkshopResponse `json:"workshop"`
+}
+
+// CreateQuoteRevisionRequest prices a new revision. Omitted costs and rates
+// are taken from the product.
+type CreateQuoteRevisionRequest struct {
+	MaterialsCost        *utils.Money `json:"materials_cost" validate:"omitempty,gte=0"`
+	HoursCost            *utils.Money `json:"hours_cost" validate:"omitempty,gte=0"`
+	ProfitPercentage     *float64     `json:"profit_percentage" validate:"omitempty,gte=0"`
+	IncludeFixedExpenses *bool        `json:"include_fixed_expenses"`
+	FixedExpenseRate     *float64     `json:"fixed_expense_rate" validate:"omitempty,gte=0"`
+	Note                 string       `json:"note" validate:"omitempty,max=1000"`
+	ExpiresInDays        int          `json:"expires_in_days" validate:"omitempty,gte=1,lte=365"`
+}
+
+type QuoteRevisionResponse struct {
+	ID                   string                 `json:"id"`
+	ProductID            string                 `json:"product_id"`
+	Number               int                    `json:"number"`
+	Status               string                 `json:"status"`
+	ProfitPercentage     float64                `json:"profit_percentage"`
+	IncludeFixedExpenses bool                   `json:"include_fixed_expenses"`
+	FixedExpenseRate     float64                `json:"fixed_expense_rate"`
+	TaxRate              float64                `json:"tax_rate"`
+	TaxInclusive         bool                   `json:"tax_inclusive"`
+	Pricing              PriceBreakdownResponse `json:"pricing"`
+	Note                 string                 `json:"note"`
+	ApprovalURL          string                 `json:"approval_url"`
+	ExpiresAt            *string                `json:"expires_at"`
+	RespondedAt          *string                `json:"responded_at"`
+	DeclineReason        string                 `json:"decline_reason,omitempty"`
+	CreatedAt            string                 `json:"created_at"`
+}
+
+type DeclineQuoteRequest struct {
+	Reason string `json:"reason" form:"reason" validate:"omitempty,max=500"`
+}
+
+type PublicQuoteLineResponse struct {
+	Description string      `json:"description"`
+	Quantity    float64     `json:"quantity"`
+	Unit        string      `json:"unit"`
+	UnitPrice   utils.Money `json:"unit_price"`
+	Amount      utils.Money `json:"amount"`
+}
+
+// PublicQuoteResponse is what the customer sees through an approval link;
+// the profit is spread over the lines.
+type PublicQuoteResponse struct {
+	Token       string                    `json:"-"`
+	Name        string                    `json:"name"`
+	Number      int                       `json:"number"`
+	Status      string                    `json:"status"`
+	Open        bool                      `json:"open"`
+	Lines       []PublicQuoteLineResponse `json:"lines"`
+	Adjustments []PublicQuoteLineResponse `json:"adjustments"` // Discounts are negative
+	Subtotal    utils.Money               `json:"subtotal"`    // Before tax
+	TaxRate     float64                   `json:"tax_rate"`
+	TaxAmount   utils.Money               `json:"tax_amount"`
+	Total       utils.Money               `json:"total"`
+	Currency    string                    `json:"currency"`
+	Locale      string                    `json:"-"`
+	Note        string                    `json:"note"`
+	DueDate     *string                   `json:"due_date"`
+	ExpiresAt   *string                   `json:"expires_at"`
+	RespondedAt *string                   `json:"responded_at"`
+	Workshop    WorkshopResponse          `json:"workshop"`
+}
+
+type StartTimerRequest struct {
+	TaskID *string `json:"task_id" validate:"omitempty,uuid"`
+	Note   string  `json:"note"`
+}
+
+type ManualTimeEntryRequest struct {
+	Minutes   float64 `json:"minutes" validate:"required,gt=0"`
+	StartedAt *string `json:"started_at" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	TaskID    *string `json:"task_id" validate:"omitempty,uuid"`
+	Note      string  `json:"note"`
+}
+
+type TimeEntryResponse struct {
+	ID         string      `json:"id"`
+	ProductID  string      `json:"product_id"`
+	TaskID     *string     `json:"task_id"`
+	StartedAt  string      `json:"started_at"`
+	EndedAt    *string     `json:"ended_at"`
+	Running    bool        `json:"running"`
+	Hours      float64     `json:"hours"`
+	HourlyRate utils.Money `json:"hourly_rate"`
+	Cost       utils.Money `json:"cost"`
+	Note       string      `json:"note"`
+}
+
+type TimeTrackingResponse struct {
+	EstimatedHours float64 `json:"estimated_hours"`
+	LoggedHours    float64 `json:"logged_hours"`
+	// Logged minus estimated hours, positive when the order took longer
+	DifferenceHours float64             `json:"difference_hours"`
+	HoursCost       utils.Money         `json:"hours_cost"`
+	Running         *TimeEntryResponse  `json:"running"`
+	Entries         []TimeEntryResponse `json:"entries"`
 }
@@ -0,0 +1,117 @@
+package products
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrFittingNotFound = errors.New("fitting not found")
+
+// defaultUpcomingDays is how far ahead the upcoming orders list looks when
+// no window is given.
+const defaultUpcomingDays = 7
+
+// GetUpcoming lists the undelivered orders due today or in the next days.
+func (s *service) GetUpcoming(userID string, days, page, limit int) (*PaginatedResponse, error) {
+	if days < 1 {
+		days = defaultUpcomingDays
+	}
+	from := today()
+	to := from.AddDate(0, 0, days)
+	return s.findDue(userID, &from, &to, page, limit)
+}
+
+// GetOverdue lists the undelivered orders whose due date has passed.
+func (s *service) GetOverdue(userID string, page, limit int) (*PaginatedResponse, error) {
+	to := today().AddDate(0, 0, -1)
+	return s.findDue(userID, nil, &to, page, limit)
+}
+
+func (s *service) findDue(userID string, from, to *time.Time, page, limit int) (*PaginatedResponse, error) {
+	offset := (page - 1) * limit
+	products, total, err := s.repo.FindDue(userID, from, to, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := []ProductResponse{}
+	for _, p := range products {
+		responses = append(responses, mapToResponse(p))
+	}
+
+	return &PaginatedResponse{
+		Data:  responses,
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	}, nil
+}
+
+func (s *service) AddFitting(userID, productID string, req CreateFittingRequest) (*FittingResponse, error) {
+	product, err := s.findOwned(userID, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	scheduledAt, err := time.Parse(time.RFC3339, req.ScheduledAt)
+	if err != nil {
+		return nil, errors.New("scheduled_at must be an RFC 3339 date and time")
+	}
+
+	fitting := &ProductFitting{
+		ProductID:   product.ID,
+		ScheduledAt: scheduledAt,
+		Note:        req.Note,
+	}
+	if err := s.repo.AddFitting(fitting); err != nil {
+		return nil, err
+	}
+
+	res := mapFittingToResponse(*fitting)
+	return &res, nil
+}
+
+func (s *service) DeleteFitting(userID, productID, fittingID string) error {
+	product, err := s.findOwned(userID, productID)
+	if err != nil {
+		return err
+	}
+
+	fitting, err := s.repo.FindFittingByID(fittingID)
+	if err != nil || fitting.ProductID != product.ID {
+		return ErrFittingNotFound
+	}
+	return s.repo.DeleteFitting(fittingID)
+}
+
+// today returns the current date at midnight, the way due dates are stored.
+func today() time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// sameDate reports whether two optional dates are the same day.
+func sameDate(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Format("2006-01-02") == b.Format("2006-01-02")
+}
+
+func mapFittingsToResponse(fittings []ProductFitting) []FittingResponse {
+	res := make([]FittingResponse, 0, len(fittings))
+	for _, f := range fittings {
+		res = append(res, mapFittingToResponse(f))
+	}
+	return res
+}
+
+func mapFittingToResponse(f ProductFitting) FittingResponse {
+	return FittingResponse{
+		ID:          f.ID.String(),
+		ProductID:   f.ProductID.String(),
+		ScheduledAt: f.ScheduledAt.Format("2006-01-02 15:04:05"),
+		Note:        f.Note,
+		CreatedAt:   f.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
@@ -1,33 +1,73 @@
 package products
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/TFX0019/api-go-gds/features/customers"
+	"github.com/TFX0019/api-go-gds/features/materials"
+	"github.com/TFX0019/api-go-gds/pkg/utils"
 	"github.com/google/uuid"
 )
 
 type Product struct {
-	ID                   uuid.UUID           `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
-	UserID               uint                `gorm:"not null"`
-	Name                 string              `gorm:"type:text;not null"`
-	ClientID             *uuid.UUID          `gorm:"type:uuid"` // Optional
-	Client               *customers.Customer `gorm:"foreignKey:ClientID"`
-	MaterialsCost        float64             `gorm:"type:numeric;not null"`
-	HoursCost            float64             `gorm:"type:numeric;not null"`
-	ProfitPercentage     float64             `gorm:"type:numeric;not null"`
-	IncludeFixedExpenses bool                `gorm:"type:boolean;not null"`
-	FixedExpenseRate     float64             `gorm:"type:numeric;not null"`
-	Subtotal             float64             `gorm:"type:numeric;not null"`
-	FixedExpensesAmount  float64             `gorm:"type:numeric;not null"`
-	BaseTotal            float64             `gorm:"type:numeric;not null"`
-	ProfitAmount         float64             `gorm:"type:numeric;not null"`
-	Total                float64             `gorm:"type:numeric;not null"`
-	Status               string              `gorm:"type:text;not null;default:'pending'"`
-	DatePaid             *time.Time          `gorm:"type:timestamp"`
-	Images               []ProductImage      `gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE;"`
-	CreatedAt            time.Time           `gorm:"not null;default:now()"`
-	UpdatedAt            time.Time           `gorm:"not null;default:now()"`
+	ID       uuid.UUID           `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	UserID   uint                `gorm:"not null"`
+	Name     string              `gorm:"type:text;not null"`
+	ClientID *uuid.UUID          `gorm:"type:uuid"` // Optional
+	Client   *customers.Customer `gorm:"foreignKey:ClientID"`
+	// Measurements the order is made with, pinned from the client's history
+	MeasurementSnapshotID *uuid.UUID                     `gorm:"type:uuid"`
+	MeasurementSnapshot   *customers.MeasurementSnapshot `gorm:"foreignKey:MeasurementSnapshotID;constraint:OnDelete:RESTRICT;"`
+	MaterialsCost         utils.Money                    `gorm:"type:numeric;not null"`
+	HoursCost             utils.Money                    `gorm:"type:numeric;not null"`
+	// Hand-entered labor cost, which HoursCost returns to without logged time
+	ManualHoursCost utils.Money `gorm:"type:numeric;not null;default:0"`
+	EstimatedHours  float64     `gorm:"type:numeric;not null;default:0"`
+	// Hours logged through time entries; once set, HoursCost comes from them
+	LoggedHours          float64     `gorm:"type:numeric;not null;default:0"`
+	ProfitPercentage     float64     `gorm:"type:numeric;not null"`
+	IncludeFixedExpenses bool        `gorm:"type:boolean;not null"`
+	FixedExpenseRate     float64     `gorm:"type:numeric;not null"`
+	Subtotal             utils.Money `gorm:"type:numeric;not null"`
+	FixedExpensesAmount  utils.Money `gorm:"type:numeric;not null"`
+	BaseTotal            utils.Money `gorm:"type:numeric;not null"`
+	ProfitAmount         utils.Money `gorm:"type:numeric;not null"`
+	// Sums of the adjustments, applied after the profit and before the tax
+	DiscountAmount  utils.Money         `gorm:"type:numeric;not null;default:0"`
+	SurchargeAmount utils.Money         `gorm:"type:numeric;not null;default:0"`
+	Adjustments     []ProductAdjustment `gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE;"`
+	Total           utils.Money         `gorm:"type:numeric;not null"`
+	// Tax copied from the profile when the order is priced, so editing the
+	// profile does not reprice existing orders
+	TaxProfileID *uuid.UUID  `gorm:"type:uuid"`
+	TaxRate      float64     `gorm:"type:numeric;not null;default:0"`
+	TaxInclusive bool        `gorm:"not null;default:false"`
+	TaxAmount    utils.Money `gorm:"type:numeric;not null;default:0"`
+	Currency     string      `gorm:"type:varchar(3);not null;default:'USD'"`
+	Status       string      `gorm:"type:text;not null;default:'pending'"`
+	DatePaid     *time.Time  `gorm:"type:timestamp"`
+	DueDate      *time.Time  `gorm:"type:date"`
+	// Set by the reminders job so each reminder goes out once; cleared when
+	// the due date changes
+	DueReminderSentAt *time.Time             `gorm:"type:timestamp"`
+	OverdueNotifiedAt *time.Time             `gorm:"type:timestamp"`
+	Fittings          []ProductFitting       `gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE;"`
+	Images            []ProductImage         `gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE;"`
+	Materials         []ProductMaterial      `gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE;"`
+	TimeEntries       []TimeEntry            `gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE;"`
+	StatusHistory     []ProductStatusHistory `gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE;"`
+	Payments          []Payment              `gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE;"`
+	// The revision the customer accepted, whose pricing the order now uses
+	AcceptedQuoteID *uuid.UUID `gorm:"type:uuid"`
+	QuoteAcceptedAt *time.Time `gorm:"type:timestamp"`
+	// Set once the materials have been taken out of stock
+	StockConsumed bool               `gorm:"not null;default:false"`
+	ShareLinks    []ProductShareLink `gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE;"`
+	Documents     []Document         `gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE;"`
+	Quotes        []QuoteRevision    `gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE;"`
+	CreatedAt     time.Time          `gorm:"not null;default:now()"`
+	UpdatedAt     time.Time          `gorm:"not null;default:now()"`
 }
 
 func (Product) TableName() string {
@@ -44,3 +84,223 @@ type ProductImage struct {
 func (ProductImage) TableName() string {
 	return "product_images"
 }
+
+// ProductMaterial is a bill of materials line. Name, unit and price are
+// copied from the material so the order keeps its cost if the material
+// changes or is deleted.
+type ProductMaterial struct {
+	ID         uuid.UUID           `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	ProductID  uuid.UUID           `gorm:"type:uuid;not null;index"`
+	MaterialID *uuid.UUID          `gorm:"type:uuid;index"`
+	Material   *materials.Material `gorm:"foreignKey:MaterialID;constraint:OnDelete:SET NULL;"`
+	Name       string              `gorm:"type:text;not null"`
+	Unit       string              `gorm:"type:text;not null"`
+	UnitPrice  utils.Money         `gorm:"type:numeric;not null"`
+	Quantity   float64             `gorm:"type:numeric;not null"`
+	CreatedAt  time.Time           `gorm:"not null;default:now()"`
+}
+
+func (ProductMaterial) TableName() string {
+	return "product_materials"
+}
+
+// ProductAdjustment is a discount or surcharge on an order, either a
+// percentage of its price or a fixed amount.
+type ProductAdjustment struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	ProductID uuid.UUID `gorm:"type:uuid;not null;index"`
+	Kind      string    `gorm:"type:text;not null"` // discount or surcharge
+	Type      string    `gorm:"type:text;not null"` // percentage or fixed
+	Value     float64   `gorm:"type:numeric;not null"`
+	Reason    string    `gorm:"type:text;not null"`
+	Position  int       `gorm:"not null;default:0"` // Order the adjustments were given in
+	CreatedAt time.Time `gorm:"not null;default:now()"`
+}
+
+func (ProductAdjustment) TableName() string {
+	return "product_adjustments"
+}
+
+// Payment is money received for an order, from the first deposit to the
+// final settlement.
+type Payment struct {
+	ID        uuid.UUID   `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	ProductID uuid.UUID   `gorm:"type:uuid;not null;index"`
+	UserID    uint        `gorm:"not null;index"`
+	Amount    utils.Money `gorm:"type:numeric;not null"`
+	Method    string      `gorm:"type:text;not null;default:'cash'"`
+	PaidAt    time.Time   `gorm:"not null"`
+	Note      string      `gorm:"type:text"`
+	CreatedAt time.Time   `gorm:"not null;default:now()"`
+}
+
+func (Payment) TableName() string {
+	return "product_payments"
+}
+
+// QuoteRevision is a priced proposal for an order. The customer accepts or
+// declines it through its approval link, and the accepted one becomes the
+// order's price.
+type QuoteRevision struct {
+	ID                   uuid.UUID   `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	ProductID            uuid.UUID   `gorm:"type:uuid;not null;uniqueIndex:idx_quote_revision_number"`
+	UserID               uint        `gorm:"not null"`
+	Number               int         `gorm:"not null;uniqueIndex:idx_quote_revision_number"`
+	MaterialsCost        utils.Money `gorm:"type:numeric;not null"`
+	HoursCost            utils.Money `gorm:"type:numeric;not null"`
+	ProfitPercentage     float64     `gorm:"type:numeric;not null"`
+	IncludeFixedExpenses bool        `gorm:"type:boolean;not null"`
+	FixedExpenseRate     float64     `gorm:"type:numeric;not null"`
+	TaxRate              float64     `gorm:"type:numeric;not null;default:0"`
+	TaxInclusive         bool        `gorm:"not null;default:false"`
+	Subtotal             utils.Money `gorm:"type:numeric;not null"`
+	FixedExpensesAmount  utils.Money `gorm:"type:numeric;not null"`
+	BaseTotal            utils.Money `gorm:"type:numeric;not null"`
+	ProfitAmount         utils.Money `gorm:"type:numeric;not null"`
+	DiscountAmount       utils.Money `gorm:"type:numeric;not null;default:0"`
+	SurchargeAmount      utils.Money `gorm:"type:numeric;not null;default:0"`
+	TaxAmount            utils.Money `gorm:"type:numeric;not null;default:0"`
+	Total                utils.Money `gorm:"type:numeric;not null"`
+	Note                 string      `gorm:"type:text"`
+	Status               string      `gorm:"type:text;not null;default:'pending'"`
+	Token                string      `gorm:"type:text;not null;uniqueIndex"`
+	ExpiresAt            *time.Time  `gorm:"type:timestamp"`
+	RespondedAt          *time.Time  `gorm:"type:timestamp"`
+	DeclineReason        string      `gorm:"type:text"`
+	CreatedAt            time.Time   `gorm:"not null;default:now()"`
+	// Adjustments as they were quoted, itemized on the approval page
+	Adjustments []AdjustmentRequest `gorm:"serializer:json"`
+}
+
+func (QuoteRevision) TableName() string {
+	return "product_quote_revisions"
+}
+
+// Open reports whether the customer can still answer the revision.
+func (q QuoteRevision) Open() bool {
+	return q.Status == QuotePending && (q.ExpiresAt == nil || q.ExpiresAt.After(time.Now()))
+}
+
+// Document is a quote, invoice or receipt generated for an order. The PDF is
+// kept so the customer gets the same copy when it is downloaded again.
+type Document struct {
+	ID              uuid.UUID   `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	ProductID       uuid.UUID   `gorm:"type:uuid;not null;index"`
+	UserID          uint        `gorm:"not null;uniqueIndex:idx_document_number"`
+	Type            string      `gorm:"type:text;not null;uniqueIndex:idx_document_number"`
+	Number          int         `gorm:"not null;uniqueIndex:idx_document_number"`
+	Path            string      `gorm:"type:text;not null"`
+	DiscountAmount  utils.Money `gorm:"type:numeric;not null;default:0"`
+	SurchargeAmount utils.Money `gorm:"type:numeric;not null;default:0"`
+	TaxAmount       utils.Money `gorm:"type:numeric;not null;default:0"`
+	Total           utils.Money `gorm:"type:numeric;not null"`
+	AmountPaid      utils.Money `gorm:"type:numeric;not null;default:0"`
+	Currency        string      `gorm:"type:varchar(3);not null;default:'USD'"`
+	CreatedAt       time.Time   `gorm:"not null;default:now()"`
+}
+
+func (Document) TableName() string {
+	return "product_documents"
+}
+
+// Code is the number printed on the document, e.g. INV-00012.
+func (d Document) Code() string {
+	return fmt.Sprintf("%s-%05d", documentPrefixes[d.Type], d.Number)
+}
+
+// DocumentSequence holds the last number given to each type of document of
+// a user, so numbering has no gaps.
+type DocumentSequence struct {
+	UserID     uint   `gorm:"primaryKey;autoIncrement:false"`
+	Type       string `gorm:"type:text;primaryKey"`
+	LastNumber int    `gorm:"not null;default:0"`
+}
+
+func (DocumentSequence) TableName() string {
+	return "document_sequences"
+}
+
+// ProductFitting is an appointment for the customer to try the garment on
+// before it is finished.
+type ProductFitting struct {
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	ProductID   uuid.UUID `gorm:"type:uuid;not null;index"`
+	ScheduledAt time.Time `gorm:"not null"`
+	Note        string    `gorm:"type:text"`
+	CreatedAt   time.Time `gorm:"not null;default:now()"`
+}
+
+func (ProductFitting) TableName() string {
+	return "product_fittings"
+}
+
+// ProductStatusHistory records each status change of a product.
+type ProductStatusHistory struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	ProductID  uuid.UUID `gorm:"type:uuid;not null;index"`
+	FromStatus string    `gorm:"type:text"` // Empty for the creation of the product
+	ToStatus   string    `gorm:"type:text;not null"`
+	ChangedBy  uint      `gorm:"not null"`
+	Note       string    `gorm:"type:text"`
+	CreatedAt  time.Time `gorm:"not null;default:now()"`
+}
+
+func (ProductStatusHistory) TableName() string {
+	return "product_status_history"
+}
+
+// TimeEntry is labor logged on a product, either with a timer or as a
+// manual duration. The hourly rate is copied from the user when the entry is
+// logged so later rate changes do not rewrite past orders.
+type TimeEntry struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	ProductID uuid.UUID  `gorm:"type:uuid;not null;index"`
+	UserID    uint       `gorm:"not null"`
+	TaskID    *uuid.UUID `gorm:"type:uuid;index"`
+	StartedAt time.Time  `gorm:"not null"`
+	// Nil while the timer is running
+	EndedAt         *time.Time  `gorm:"type:timestamp"`
+	DurationSeconds int64       `gorm:"not null;default:0"`
+	HourlyRate      utils.Money `gorm:"type:numeric;not null;default:0"`
+	Note            string      `gorm:"type:text"`
+	CreatedAt       time.Time   `gorm:"not null;default:now()"`
+}
+
+func (TimeEntry) TableName() string {
+	return "product_time_entries"
+}
+
+// Hours returns the logged duration, counting a running timer up to now.
+func (e TimeEntry) Hours() float64 {
+	seconds := e.DurationSeconds
+	if e.EndedAt == nil {
+		seconds = int64(time.Since(e.StartedAt).Seconds())
+	}
+	return float64(seconds) / 3600
+}
+
+// cost is the labor cost of the entry at the rate it was logged with.
+func (e TimeEntry) cost() utils.Money {
+	return e.HourlyRate.Mul(e.Hours())
+}
+
+// ProductShareLink gives the customer read-only access to the status of
+// their order without logging in.
+type ProductShareLink struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	ProductID uuid.UUID  `gorm:"type:uuid;not null;index"`
+	UserID    uint       `gorm:"not null"`
+	Token     string     `gorm:"type:text;not null;uniqueIndex"`
+	ExpiresAt *time.Time `gorm:"type:timestamp"`
+	RevokedAt *time.Time `gorm:"type:timestamp"`
+	CreatedAt time.Time  `gorm:"not null;default:now()"`
+}
+
+func (ProductShareLink) TableName() string {
+	return "product_share_links"
+}
+
+// Active reports whether the link can still be used.
+func (l ProductShareLink) Active() bool {
+	return l.RevokedAt == nil && (l.ExpiresAt == nil || l.ExpiresAt.After(time.Now()))
+}
@@ -0,0 +1,169 @@
+package products
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/TFX0019/api-go-gds/pkg/utils"
+)
+
+var (
+	ErrPaymentNotFound = errors.New("payment not found")
+	ErrOverpayment     = errors.New("payment exceeds the outstanding balance")
+)
+
+const defaultPaymentMethod = "cash"
+
+func (s *service) GetPayments(userID, productID string) (*PaymentsResponse, error) {
+	product, err := s.findOwned(userID, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &PaymentsResponse{
+		Currency:           product.Currency,
+		Total:              product.Total,
+		AmountPaid:         amountPaid(*product),
+		OutstandingBalance: outstandingBalance(*product),
+		Payments:           make([]PaymentResponse, 0, len(product.Payments)),
+	}
+	for _, p := range product.Payments {
+		res.Payments = append(res.Payments, mapPaymentToResponse(p))
+	}
+	return res, nil
+}
+
+// AddPayment records a deposit or partial payment. The payment that settles
+// the order sets its payment date and moves it to paid when its status
+// allows it.
+func (s *service) AddPayment(userID, productID string, req CreatePaymentRequest) (*PaymentResponse, error) {
+	product, err := s.findOwned(userID, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	paidAt := time.Now()
+	if req.PaidAt != nil && *req.PaidAt != "" {
+		date, err := parseDate(req.PaidAt)
+		if err != nil {
+			return nil, err
+		}
+		paidAt = *date
+	}
+
+	method := req.Method
+	if method == "" {
+		method = defaultPaymentMethod
+	}
+
+	payment := &Payment{
+		ProductID: product.ID,
+		UserID:    product.UserID,
+		Amount:    req.Amount.Round(),
+		Method:    method,
+		PaidAt:    paidAt,
+		Note:      req.Note,
+	}
+
+	// The balance is checked against the locked order so concurrent
+	// payments cannot both pass it
+	err = s.repo.AddPayment(product.ID, payment, func(product *Product) (*ProductStatusHistory, error) {
+		if product.Status == StatusCancelled {
+			return nil, errors.New("cannot add payments to a cancelled order")
+		}
+
+		balance := outstandingBalance(*product)
+		if payment.Amount.GreaterThan(balance.Add(priceTolerance)) {
+			return nil, fmt.Errorf("%w (%s)", ErrOverpayment, balance)
+		}
+
+		var entry *ProductStatusHistory
+		if !amountPaid(*product).Add(payment.Amount).LessThan(product.Total.Sub(priceTolerance)) {
+			if product.DatePaid == nil {
+				product.DatePaid = &paidAt
+			}
+			if CanTransition(product.Status, StatusPaid) {
+				entry = &ProductStatusHistory{
+					ProductID:  product.ID,
+					FromStatus: product.Status,
+					ToStatus:   StatusPaid,
+					ChangedBy:  product.UserID,
+					Note:       "Fully paid",
+				}
+				product.Status = StatusPaid
+			}
+		}
+		return entry, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := mapPaymentToResponse(*payment)
+	return &res, nil
+}
+
+// DeletePayment removes a payment recorded by mistake. Paid orders have to
+// be moved back to another status first.
+func (s *service) DeletePayment(userID, productID, paymentID string) error {
+	product, err := s.findOwned(userID, productID)
+	if err != nil {
+		return err
+	}
+
+	payment, err := s.repo.FindPaymentByID(paymentID)
+	if err != nil || payment.ProductID != product.ID {
+		return ErrPaymentNotFound
+	}
+	if product.Status == StatusPaid {
+		return fmt.Errorf("%w: change the status of the order before removing its payments", ErrInvalidTransition)
+	}
+
+	// The order is no longer settled
+	if amountPaid(*product).Sub(payment.Amount).LessThan(product.Total.Sub(priceTolerance)) {
+		product.DatePaid = nil
+	}
+
+	product.Materials = nil
+	product.Payments = nil
+	return s.repo.DeletePayment(product, paymentID)
+}
+
+// settlingPayment is the payment recorded for the remaining balance when an
+// order is marked as paid by hand.
+func settlingPayment(p Product) *Payment {
+	balance := outstandingBalance(p)
+	if !balance.IsPositive() {
+		return nil
+	}
+	return &Payment{
+		ProductID: p.ID,
+		UserID:    p.UserID,
+		Amount:    balance,
+		Method:    "other",
+		PaidAt:    time.Now(),
+		Note:      "Balance settled when marked as paid",
+	}
+}
+
+// amountPaid is the sum of the payments received for the order.
+func amountPaid(p Product) utils.Money {
+	var paid utils.Money
+	for _, payment := range p.Payments {
+		paid = paid.Add(payment.Amount)
+	}
+	return paid
+}
+
+func mapPaymentToResponse(p Payment) PaymentResponse {
+	return PaymentResponse{
+		ID:        p.ID.String(),
+		ProductID: p.ProductID.String(),
+		Amount:    p.Amount,
+		Method:    p.Method,
+		PaidAt:    p.PaidAt.Format("2006-01-02 15:04:05"),
+		Note:      p.Note,
+		CreatedAt: p.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
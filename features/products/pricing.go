@@ -0,0 +1,212 @@
+package products
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/TFX0019/api-go-gds/pkg/utils"
+)
+
+// ErrPriceMismatch is returned when a client sends derived amounts that do
+// not match the ones computed by the server.
+var ErrPriceMismatch = errors.New("price mismatch")
+
+// priceTolerance absorbs rounding differences between the apps and the server.
+var priceTolerance = utils.NewMoney(0.01)
+
+// PriceBreakdown holds every amount derived from a product's costs.
+type PriceBreakdown struct {
+	MaterialsCost       utils.Money
+	HoursCost           utils.Money
+	Subtotal            utils.Money
+	FixedExpensesAmount utils.Money
+	BaseTotal           utils.Money
+	ProfitAmount        utils.Money
+	DiscountAmount      utils.Money
+	SurchargeAmount     utils.Money
+	TaxAmount           utils.Money
+	Total               utils.Money
+}
+
+// CalculatePrice applies the pricing formula:
+//
+//	Subtotal      = materials + hours
+//	Fixed         = Subtotal * rate / 100, when fixed expenses are included
+//	BaseTotal     = Subtotal + Fixed
+//	Profit        = BaseTotal * percentage / 100
+//	Price         = BaseTotal + Profit - Discounts + Surcharges
+//	Tax           = Price * tax rate / 100
+//	Total         = Price + Tax
+//
+// When the tax is inclusive the price already contains it: Total stays
+// Price and the tax comes out of the profit.
+func CalculatePrice(in PricingRequest) PriceBreakdown {
+	b := PriceBreakdown{
+		MaterialsCost: in.MaterialsCost.Round(),
+		HoursCost:     in.HoursCost.Round(),
+	}
+
+	b.Subtotal = b.MaterialsCost.Add(b.HoursCost)
+	if in.IncludeFixedExpenses {
+		b.FixedExpensesAmount = b.Subtotal.Percent(in.FixedExpenseRate).Round()
+	}
+	b.BaseTotal = b.Subtotal.Add(b.FixedExpensesAmount)
+	b.ProfitAmount = b.BaseTotal.Percent(in.ProfitPercentage).Round()
+	b.Total = b.BaseTotal.Add(b.ProfitAmount)
+
+	for i, amount := range adjustmentAmounts(b.Total, in.Adjustments) {
+		if in.Adjustments[i].Kind == AdjustmentDiscount {
+			b.DiscountAmount = b.DiscountAmount.Add(amount)
+		} else {
+			b.SurchargeAmount = b.SurchargeAmount.Add(amount)
+		}
+	}
+	b.Total = b.Total.Sub(b.DiscountAmount).Add(b.SurchargeAmount)
+
+	if in.TaxInclusive {
+		b.TaxAmount = b.Total.Mul(in.TaxRate).Div(100 + in.TaxRate).Round()
+		b.ProfitAmount = b.ProfitAmount.Sub(b.TaxAmount)
+	} else {
+		b.TaxAmount = b.Total.Percent(in.TaxRate).Round()
+		b.Total = b.Total.Add(b.TaxAmount)
+	}
+
+	return b
+}
+
+// clientAmounts are the derived amounts a client may send along its costs.
+type clientAmounts struct {
+	Subtotal            *utils.Money
+	FixedExpensesAmount *utils.Money
+	BaseTotal           *utils.Money
+	ProfitAmount        *utils.Money
+	DiscountAmount      *utils.Money
+	SurchargeAmount     *utils.Money
+	TaxAmount           *utils.Money
+	Total               *utils.Money
+}
+
+// Verify rejects client amounts that differ from the computed ones.
+func (b PriceBreakdown) Verify(sent clientAmounts) error {
+	checks := []struct {
+		name     string
+		sent     *utils.Money
+		computed utils.Money
+	}{
+		{"subtotal", sent.Subtotal, b.Subtotal},
+		{"fixed_expenses_amount", sent.FixedExpensesAmount, b.FixedExpensesAmount},
+		{"base_total", sent.BaseTotal, b.BaseTotal},
+		{"profit_amount", sent.ProfitAmount, b.ProfitAmount},
+		{"discount_amount", sent.DiscountAmount, b.DiscountAmount},
+		{"surcharge_amount", sent.SurchargeAmount, b.SurchargeAmount},
+		{"tax_amount", sent.TaxAmount, b.TaxAmount},
+		{"total", sent.Total, b.Total},
+	}
+
+	for _, c := range checks {
+		if c.sent != nil && c.sent.Sub(c.computed).Abs().GreaterThan(priceTolerance) {
+			return fmt.Errorf("%w: %s is %s but should be %s", ErrPriceMismatch, c.name, c.sent, c.computed)
+		}
+	}
+	return nil
+}
+
+// equal reports whether both breakdowns have the same amounts.
+func (b PriceBreakdown) equal(o PriceBreakdown) bool {
+	return b.MaterialsCost.Equal(o.MaterialsCost) &&
+		b.HoursCost.Equal(o.HoursCost) &&
+		b.Subtotal.Equal(o.Subtotal) &&
+		b.FixedExpensesAmount.Equal(o.FixedExpensesAmount) &&
+		b.BaseTotal.Equal(o.BaseTotal) &&
+		b.ProfitAmount.Equal(o.ProfitAmount) &&
+		b.DiscountAmount.Equal(o.DiscountAmount) &&
+		b.SurchargeAmount.Equal(o.SurchargeAmount) &&
+		b.TaxAmount.Equal(o.TaxAmount) &&
+		b.Total.Equal(o.Total)
+}
+
+// breakdown returns the price stored on the product.
+func (p Product) breakdown() PriceBreakdown {
+	return PriceBreakdown{
+		MaterialsCost:       p.MaterialsCost,
+		HoursCost:           p.HoursCost,
+		Subtotal:            p.Subtotal,
+		FixedExpensesAmount: p.FixedExpensesAmount,
+		BaseTotal:           p.BaseTotal,
+		ProfitAmount:        p.ProfitAmount,
+		DiscountAmount:      p.DiscountAmount,
+		SurchargeAmount:     p.SurchargeAmount,
+		TaxAmount:           p.TaxAmount,
+		Total:               p.Total,
+	}
+}
+
+// apply stores the breakdown on the product.
+func (b PriceBreakdown) apply(p *Product) {
+	p.MaterialsCost = b.MaterialsCost
+	p.HoursCost = b.HoursCost
+	if p.LoggedHours == 0 {
+		p.ManualHoursCost = b.HoursCost
+	}
+	p.Subtotal = b.Subtotal
+	p.FixedExpensesAmount = b.FixedExpensesAmount
+	p.BaseTotal = b.BaseTotal
+	p.ProfitAmount = b.ProfitAmount
+	p.DiscountAmount = b.DiscountAmount
+	p.SurchargeAmount = b.SurchargeAmount
+	p.TaxAmount = b.TaxAmount
+	p.Total = b.Total
+}
+
+func (r CreateProductRequest) pricing() (PricingRequest, clientAmounts) {
+	return PricingRequest{
+		MaterialsCost:        r.MaterialsCost,
+		HoursCost:            r.HoursCost,
+		ProfitPercentage:     r.ProfitPercentage,
+		IncludeFixedExpenses: r.IncludeFixedExpenses,
+		FixedExpenseRate:     r.FixedExpenseRate,
+		Adjustments:          r.Adjustments,
+	}, clientAmounts{
+		Subtotal:            r.Subtotal,
+		FixedExpensesAmount: r.FixedExpensesAmount,
+		BaseTotal:           r.BaseTotal,
+		ProfitAmount:        r.ProfitAmount,
+		DiscountAmount:      r.DiscountAmount,
+		SurchargeAmount:     r.SurchargeAmount,
+		TaxAmount:           r.TaxAmount,
+		Total:               r.Total,
+	}
+}
+
+func (r UpdateProductRequest) pricing() (PricingRequest, clientAmounts) {
+	return CreateProductRequest{
+		MaterialsCost:        r.MaterialsCost,
+		HoursCost:            r.HoursCost,
+		ProfitPercentage:     r.ProfitPercentage,
+		IncludeFixedExpenses: r.IncludeFixedExpenses,
+		FixedExpenseRate:     r.FixedExpenseRate,
+		Subtotal:             r.Subtotal,
+		FixedExpensesAmount:  r.FixedExpensesAmount,
+		BaseTotal:            r.BaseTotal,
+		ProfitAmount:         r.ProfitAmount,
+		DiscountAmount:       r.DiscountAmount,
+		SurchargeAmount:      r.SurchargeAmount,
+		TaxAmount:            r.TaxAmount,
+		Total:                r.Total,
+	}.pricing()
+}
+
+func mapBreakdownToResponse(b PriceBreakdown) PriceBreakdownResponse {
+	return PriceBreakdownResponse{
+		MaterialsCost:       b.MaterialsCost,
+		HoursCost:           b.HoursCost,
+		Subtotal:            b.Subtotal,
+		FixedExpensesAmount: b.FixedExpensesAmount,
+		BaseTotal:           b.BaseTotal,
+		ProfitAmount:        b.ProfitAmount,
+		DiscountAmount:      b.DiscountAmount,
+		SurchargeAmount:     b.SurchargeAmount,
+		TaxAmount:           b.TaxAmount,
+		Total:               b.Total,
+	}
+}
@@ -0,0 +1,299 @@
+package products
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/TFX0019/api-go-gds/pkg/utils"
+	"github.com/xuri/excelize/v2"
+)
+
+// GetProfitLoss reports the period in the query's currency, converting
+// orders priced in other currencies with the current exchange rates.
+func (s *service) GetProfitLoss(userID string, query ProfitLossQuery) (*ProfitLossResponse, error) {
+	currency, err := s.reportCurrency(userID, query.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	from, to, err := profitLossPeriod(query)
+	if err != nil {
+		return nil, err
+	}
+	if query.Compare && from == nil {
+		return nil, errors.New("comparing needs a period: set from and to, a year or a month")
+	}
+
+	convert := s.converter(currency)
+	res := &ProfitLossResponse{
+		Currency: currency,
+		From:     formatDate(from),
+		To:       formatDate(to),
+		GroupBy:  query.GroupBy,
+	}
+
+	rows, err := s.repo.GetProfitLoss(userID, from, to, "")
+	if err != nil {
+		return nil, err
+	}
+	if res.ProfitLossAmounts, err = sumProfitLoss(rows, convert); err != nil {
+		return nil, err
+	}
+
+	if query.GroupBy != "" {
+		rows, err := s.repo.GetProfitLoss(userID, from, to, query.GroupBy)
+		if err != nil {
+			return nil, err
+		}
+		if res.Groups, err = groupProfitLoss(rows, query.GroupBy, convert); err != nil {
+			return nil, err
+		}
+	}
+
+	if query.Compare {
+		prevFrom, prevTo := previousPeriod(*from, *to)
+		rows, err := s.repo.GetProfitLoss(userID, &prevFrom, &prevTo, "")
+		if err != nil {
+			return nil, err
+		}
+		prev := &ProfitLossPreviousPeriod{
+			From: prevFrom.Format("2006-01-02"),
+			To:   prevTo.Format("2006-01-02"),
+		}
+		if prev.ProfitLossAmounts, err = sumProfitLoss(rows, convert); err != nil {
+			return nil, err
+		}
+		prev.ReceivedChange = percentChange(prev.TotalReceived, res.TotalReceived)
+		prev.RevenueChange = percentChange(prev.TotalRevenue, res.TotalRevenue)
+		prev.ProfitChange = percentChange(prev.TotalProfitAmount, res.TotalProfitAmount)
+		res.Previous = prev
+	}
+
+	return res, nil
+}
+
+// ExportProfitLoss writes the report as a CSV or XLSX file, one line per
+// group followed by the totals and the previous period when compared.
+func (s *service) ExportProfitLoss(userID string, query ProfitLossQuery) ([]byte, error) {
+	report, err := s.GetProfitLoss(userID, query)
+	if err != nil {
+		return nil, err
+	}
+
+	records := [][]string{{"Group", "Orders", "Received", "Tax", "Revenue", "Materials cost", "Hours cost", "Fixed expenses", "Profit", "Discounts", "Surcharges", "Currency"}}
+	record := func(label string, a ProfitLossAmounts) []string {
+		return []string{
+			label,
+			fmt.Sprintf("%d", a.OrderCount),
+			a.TotalReceived.String(),
+			a.TotalTaxAmount.String(),
+			a.TotalRevenue.String(),
+			a.TotalMaterialsCost.String(),
+			a.TotalHoursCost.String(),
+			a.TotalFixedExpensesAmount.String(),
+			a.TotalProfitAmount.String(),
+			a.TotalDiscountAmount.String(),
+			a.TotalSurchargeAmount.String(),
+			report.Currency,
+		}
+	}
+	for _, g := range report.Groups {
+		records = append(records, record(g.Label, g.ProfitLossAmounts))
+	}
+	total := "Total"
+	if report.From != nil {
+		total = fmt.Sprintf("Total %s to %s", *report.From, *report.To)
+	}
+	records = append(records, record(total, report.ProfitLossAmounts))
+	if report.Previous != nil {
+		label := fmt.Sprintf("Previous %s to %s", report.Previous.From, report.Previous.To)
+		records = append(records, record(label, report.Previous.ProfitLossAmounts))
+	}
+
+	if query.Format == "xlsx" {
+		return profitLossXLSX(records)
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.WriteAll(records); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// profitLossPeriod returns the dates the query covers, both nil for all
+// time.
+func profitLossPeriod(query ProfitLossQuery) (*time.Time, *time.Time, error) {
+	now := time.Now()
+	if query.From != "" || query.To != "" {
+		to := today()
+		if query.To != "" {
+			date, err := parseDate(&query.To)
+			if err != nil {
+				return nil, nil, err
+			}
+			to = *date
+		}
+		from := time.Date(to.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+		if query.From != "" {
+			date, err := parseDate(&query.From)
+			if err != nil {
+				return nil, nil, err
+			}
+			from = *date
+		}
+		if to.Before(from) {
+			return nil, nil, errors.New("from must not be after to")
+		}
+		return &from, &to, nil
+	}
+
+	month := parseMonth(query.Month)
+	if query.Year == 0 && month == 0 {
+		return nil, nil, nil
+	}
+
+	year := query.Year
+	if year == 0 {
+		year = now.Year()
+	}
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(1, 0, -1)
+	if month > 0 {
+		from = time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+		to = from.AddDate(0, 1, -1)
+	}
+	return &from, &to, nil
+}
+
+// previousPeriod returns the period of the same length ending the day
+// before from. Periods of whole months go back as many calendar months.
+func previousPeriod(from, to time.Time) (time.Time, time.Time) {
+	prevTo := from.AddDate(0, 0, -1)
+	if from.Day() == 1 && to.AddDate(0, 0, 1).Day() == 1 {
+		months := (to.Year()-from.Year())*12 + int(to.Month()-from.Month()) + 1
+		return from.AddDate(0, -months, 0), prevTo
+	}
+	days := int(to.Sub(from).Hours()/24) + 1
+	return from.AddDate(0, 0, -days), prevTo
+}
+
+// sumProfitLoss adds up the rows of each currency.
+func sumProfitLoss(rows []ProfitLossRow, convert func(amount utils.Money, from string) (utils.Money, error)) (ProfitLossAmounts, error) {
+	var res ProfitLossAmounts
+	for _, row := range rows {
+		if err := res.add(row, convert); err != nil {
+			return res, err
+		}
+	}
+	res.round()
+	return res, nil
+}
+
+// groupProfitLoss merges the rows of each group, which come sorted by group.
+func groupProfitLoss(rows []ProfitLossRow, groupBy string, convert func(amount utils.Money, from string) (utils.Money, error)) ([]ProfitLossGroupResponse, error) {
+	groups := []ProfitLossGroupResponse{}
+	for _, row := range rows {
+		if n := len(groups); n == 0 || groups[n-1].Key != row.GroupKey {
+			label := row.GroupLabel
+			if groupBy == "customer" && row.GroupKey == "" {
+				label = "No customer"
+			}
+			groups = append(groups, ProfitLossGroupResponse{Key: row.GroupKey, Label: label})
+		}
+		if err := groups[len(groups)-1].add(row, convert); err != nil {
+			return nil, err
+		}
+	}
+	for i := range groups {
+		groups[i].round()
+	}
+	return groups, nil
+}
+
+func (a *ProfitLossAmounts) add(row ProfitLossRow, convert func(amount utils.Money, from string) (utils.Money, error)) error {
+	amounts := []struct {
+		from utils.Money
+		to   *utils.Money
+	}{
+		{row.TotalMaterialsCost, &a.TotalMaterialsCost},
+		{row.TotalHoursCost, &a.TotalHoursCost},
+		{row.TotalFixedExpensesAmount, &a.TotalFixedExpensesAmount},
+		{row.TotalProfitAmount, &a.TotalProfitAmount},
+		{row.TotalDiscountAmount, &a.TotalDiscountAmount},
+		{row.TotalSurchargeAmount, &a.TotalSurchargeAmount},
+		{row.TotalTaxAmount, &a.TotalTaxAmount},
+		{row.TotalReceived, &a.TotalReceived},
+	}
+	for _, amount := range amounts {
+		converted, err := convert(amount.from, row.Currency)
+		if err != nil {
+			return err
+		}
+		*amount.to = amount.to.Add(converted)
+	}
+	a.OrderCount += row.OrderCount
+	return nil
+}
+
+func (a *ProfitLossAmounts) round() {
+	a.TotalMaterialsCost = a.TotalMaterialsCost.Round()
+	a.TotalHoursCost = a.TotalHoursCost.Round()
+	a.TotalFixedExpensesAmount = a.TotalFixedExpensesAmount.Round()
+	a.TotalProfitAmount = a.TotalProfitAmount.Round()
+	a.TotalDiscountAmount = a.TotalDiscountAmount.Round()
+	a.TotalSurchargeAmount = a.TotalSurchargeAmount.Round()
+	a.TotalTaxAmount = a.TotalTaxAmount.Round()
+	a.TotalReceived = a.TotalReceived.Round()
+	a.TotalRevenue = a.TotalReceived.Sub(a.TotalTaxAmount)
+}
+
+// percentChange returns how much current moved from previous in percent,
+// rounded to two decimals.
+func percentChange(previous, current utils.Money) *float64 {
+	if previous.IsZero() {
+		return nil
+	}
+	change := current.Sub(previous).Float64() / previous.Abs().Float64() * 100
+	change = math.Round(change*100) / 100
+	return &change
+}
+
+func profitLossXLSX(records [][]string) ([]byte, error) {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	sheet := "Profit and loss"
+	if err := f.SetSheetName("Sheet1", sheet); err != nil {
+		return nil, err
+	}
+	for i, record := range records {
+		for j, value := range record {
+			cell, err := excelize.CoordinatesToCellName(j+1, i+1)
+			if err != nil {
+				return nil, err
+			}
+			// Numbers are written as numbers so the sheet can sum them
+			var v interface{} = value
+			if i > 0 && j > 0 && j < len(record)-1 {
+				if n, err := utils.ParseMoney(value); err == nil {
+					v = n.Float64()
+				}
+			}
+			if err := f.SetCellValue(sheet, cell, v); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	buf, err := f.WriteToBuffer()
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
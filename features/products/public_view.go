@@ -0,0 +1,153 @@
+package products
+
+import (
+	"html/template"
+	"strings"
+
+	"github.com/TFX0019/api-go-gds/pkg/utils"
+)
+
+var statusLabels = map[string]string{
+	"pending":    "Pending",
+	"in_process": "In progress",
+	"complete":   "Ready",
+	"delivered":  "Delivered",
+	"paid":       "Paid",
+	"cancelled":  "Cancelled",
+}
+
+var quoteStatusLabels = map[string]string{
+	QuotePending:    "Waiting for your answer",
+	QuoteAccepted:   "Accepted",
+	QuoteDeclined:   "Declined",
+	QuoteSuperseded: "Replaced by a newer quote",
+	QuoteExpired:    "Expired",
+}
+
+var publicOrderTemplate = template.Must(template.New("order").Funcs(template.FuncMap{
+	"status": func(s string) string {
+		if label, ok := statusLabels[s]; ok {
+			return label
+		}
+		return strings.ReplaceAll(s, "_", " ")
+	},
+	"money": utils.FormatMoney,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>{{.Name}}{{if .Workshop.Name}} · {{.Workshop.Name}}{{end}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Roboto, sans-serif; background: #f6f5f3; color: #222; margin: 0; }
+main { max-width: 560px; margin: 0 auto; padding: 24px 16px; }
+header { display: flex; align-items: center; gap: 12px; margin-bottom: 24px; }
+header img { width: 48px; height: 48px; border-radius: 50%; object-fit: cover; }
+.card { background: #fff; border-radius: 12px; padding: 20px; box-shadow: 0 1px 3px rgba(0,0,0,.08); }
+.status { display: inline-block; padding: 4px 12px; border-radius: 999px; background: #eee; font-weight: 600; }
+.status.complete, .status.paid { background: #dff3e4; color: #1d6b34; }
+.status.in_process { background: #fff1d6; color: #8a5a00; }
+dl { display: grid; grid-template-columns: auto 1fr; gap: 8px 16px; }
+dt { color: #666; }
+.images { display: grid; grid-template-columns: repeat(auto-fill, minmax(140px, 1fr)); gap: 8px; margin-top: 16px; }
+.images img { width: 100%; border-radius: 8px; }
+</style>
+</head>
+<body>
+<main>
+<header>
+{{if .Workshop.AvatarURL}}<img src="{{.Workshop.AvatarURL}}" alt="">{{end}}
+<strong>{{.Workshop.Name}}</strong>
+</header>
+<div class="card">
+<h1>{{.Name}}</h1>
+<p><span class="status {{.Status}}">{{status .Status}}</span></p>
+<dl>
+{{if .DueDate}}<dt>Due date</dt><dd>{{.DueDate}}</dd>{{end}}
+<dt>Total</dt><dd>{{money .Total .Currency .Locale}}</dd>
+<dt>Outstanding balance</dt><dd>{{money .OutstandingBalance .Currency .Locale}}</dd>
+</dl>
+{{if .Images}}<div class="images">{{range .Images}}<img src="{{.}}" alt="">{{end}}</div>{{end}}
+</div>
+</main>
+</body>
+</html>`))
+
+type quotePage struct {
+	Quote   *PublicQuoteResponse
+	Message string
+}
+
+var publicQuoteTemplate = template.Must(template.New("quote").Funcs(template.FuncMap{
+	"status": func(s string) string {
+		if label, ok := quoteStatusLabels[s]; ok {
+			return label
+		}
+		return s
+	},
+	"money": utils.FormatMoney,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>{{.Quote.Name}}{{if .Quote.Workshop.Name}} · {{.Quote.Workshop.Name}}{{end}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Roboto, sans-serif; background: #f6f5f3; color: #222; margin: 0; }
+main { max-width: 560px; margin: 0 auto; padding: 24px 16px; }
+header { display: flex; align-items: center; gap: 12px; margin-bottom: 24px; }
+header img { width: 48px; height: 48px; border-radius: 50%; object-fit: cover; }
+.card { background: #fff; border-radius: 12px; padding: 20px; box-shadow: 0 1px 3px rgba(0,0,0,.08); }
+.status { display: inline-block; padding: 4px 12px; border-radius: 999px; background: #eee; font-weight: 600; }
+.status.accepted { background: #dff3e4; color: #1d6b34; }
+.status.pending { background: #fff1d6; color: #8a5a00; }
+.message { background: #e8f0fe; border-radius: 8px; padding: 12px; margin-bottom: 16px; }
+table { width: 100%; border-collapse: collapse; margin: 16px 0; }
+th, td { padding: 6px 4px; text-align: right; }
+th:first-child, td:first-child { text-align: left; }
+tfoot td { border-top: 1px solid #ddd; font-weight: 600; }
+form { margin-top: 12px; }
+textarea { width: 100%; box-sizing: border-box; min-height: 64px; }
+button { padding: 10px 20px; border: 0; border-radius: 8px; font-weight: 600; cursor: pointer; }
+.accept { background: #1d6b34; color: #fff; }
+.decline { background: #eee; }
+</style>
+</head>
+<body>
+<main>
+<header>
+{{if .Quote.Workshop.AvatarURL}}<img src="{{.Quote.Workshop.AvatarURL}}" alt="">{{end}}
+<strong>{{.Quote.Workshop.Name}}</strong>
+</header>
+{{if .Message}}<p class="message">{{.Message}}</p>{{end}}
+<div class="card">
+<h1>{{.Quote.Name}}</h1>
+<p>Quote #{{.Quote.Number}} <span class="status {{.Quote.Status}}">{{status .Quote.Status}}</span></p>
+<table>
+<thead><tr><th>Description</th><th>Qty</th><th>Amount</th></tr></thead>
+<tbody>
+{{range .Quote.Lines}}<tr><td>{{.Description}}</td><td>{{.Quantity}} {{.Unit}}</td><td>{{money .Amount $.Quote.Currency $.Quote.Locale}}</td></tr>
+{{end}}</tbody>
+<tfoot>
+{{range .Quote.Adjustments}}<tr><td colspan="2">{{.Description}}</td><td>{{money .Amount $.Quote.Currency $.Quote.Locale}}</td></tr>
+{{end}}{{if not .Quote.TaxAmount.IsZero}}<tr><td colspan="2">Subtotal</td><td>{{money .Quote.Subtotal .Quote.Currency .Quote.Locale}}</td></tr>
+<tr><td colspan="2">Tax {{.Quote.TaxRate}}%</td><td>{{money .Quote.TaxAmount .Quote.Currency .Quote.Locale}}</td></tr>
+{{end}}<tr><td colspan="2">Total</td><td>{{money .Quote.Total .Quote.Currency .Quote.Locale}}</td></tr>
+</tfoot>
+</table>
+{{if .Quote.Note}}<p>{{.Quote.Note}}</p>{{end}}
+{{if .Quote.DueDate}}<p>Due date: {{.Quote.DueDate}}</p>{{end}}
+{{if .Quote.Open}}
+{{if .Quote.ExpiresAt}}<p>Valid until {{.Quote.ExpiresAt}}</p>{{end}}
+<form method="post" action="/quotes/{{.Quote.Token}}/accept"><button class="accept" type="submit">Accept quote</button></form>
+<form method="post" action="/quotes/{{.Quote.Token}}/decline">
+<textarea name="reason" maxlength="500" placeholder="Tell us what you would change (optional)"></textarea>
+<button class="decline" type="submit">Decline</button>
+</form>
+{{end}}
+</div>
+</main>
+</body>
+</html>`))
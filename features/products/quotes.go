@@ -0,0 +1,295 @@
+package products
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrQuoteNotFound = errors.New("quote not found")
+	ErrQuoteClosed   = errors.New("this quote can no longer be answered")
+	// ErrPriceAccepted is returned when an edit would change the price the
+	// client accepted through a quote.
+	ErrPriceAccepted = errors.New("the client accepted this order's price, send a new quote to change it")
+)
+
+const (
+	QuotePending    = "pending"
+	QuoteAccepted   = "accepted"
+	QuoteDeclined   = "declined"
+	QuoteSuperseded = "superseded"
+	QuoteExpired    = "expired"
+)
+
+// CreateQuoteRevision prices a new proposal for the order and gives it an
+// approval link. Earlier revisions still waiting for an answer are
+// superseded by it.
+func (s *service) CreateQuoteRevision(userID, productID string, req CreateQuoteRevisionRequest) (*QuoteRevisionResponse, error) {
+	product, err := s.findOwned(userID, productID)
+	if err != nil {
+		return nil, err
+	}
+	if product.Status == StatusCancelled {
+		return nil, errors.New("cannot quote a cancelled order")
+	}
+
+	pricing := PricingRequest{
+		MaterialsCost:        product.MaterialsCost,
+		HoursCost:            product.HoursCost,
+		ProfitPercentage:     product.ProfitPercentage,
+		IncludeFixedExpenses: product.IncludeFixedExpenses,
+		FixedExpenseRate:     product.FixedExpenseRate,
+		Adjustments:          adjustmentRequests(product.Adjustments),
+		TaxRate:              product.TaxRate,
+		TaxInclusive:         product.TaxInclusive,
+	}
+	if req.MaterialsCost != nil {
+		pricing.MaterialsCost = *req.MaterialsCost
+	}
+	if req.HoursCost != nil {
+		pricing.HoursCost = *req.HoursCost
+	}
+	if req.ProfitPercentage != nil {
+		pricing.ProfitPercentage = *req.ProfitPercentage
+	}
+	if req.IncludeFixedExpenses != nil {
+		pricing.IncludeFixedExpenses = *req.IncludeFixedExpenses
+	}
+	if req.FixedExpenseRate != nil {
+		pricing.FixedExpenseRate = *req.FixedExpenseRate
+	}
+	price := CalculatePrice(pricing)
+
+	token, err := generateShareToken()
+	if err != nil {
+		return nil, err
+	}
+
+	quote := &QuoteRevision{
+		ProductID:            product.ID,
+		UserID:               product.UserID,
+		MaterialsCost:        price.MaterialsCost,
+		HoursCost:            price.HoursCost,
+		ProfitPercentage:     pricing.ProfitPercentage,
+		IncludeFixedExpenses: pricing.IncludeFixedExpenses,
+		FixedExpenseRate:     pricing.FixedExpenseRate,
+		TaxRate:              pricing.TaxRate,
+		TaxInclusive:         pricing.TaxInclusive,
+		Subtotal:             price.Subtotal,
+		FixedExpensesAmount:  price.FixedExpensesAmount,
+		BaseTotal:            price.BaseTotal,
+		ProfitAmount:         price.ProfitAmount,
+		DiscountAmount:       price.DiscountAmount,
+		SurchargeAmount:      price.SurchargeAmount,
+		Adjustments:          pricing.Adjustments,
+		TaxAmount:            price.TaxAmount,
+		Total:                price.Total,
+		Note:                 req.Note,
+		Status:               QuotePending,
+		Token:                token,
+	}
+	if req.ExpiresInDays > 0 {
+		expiresAt := time.Now().AddDate(0, 0, req.ExpiresInDays)
+		quote.ExpiresAt = &expiresAt
+	}
+
+	if err := s.repo.CreateQuoteRevision(quote); err != nil {
+		return nil, err
+	}
+
+	res := mapQuoteToResponse(*quote)
+	return &res, nil
+}
+
+func (s *service) GetQuoteRevisions(userID, productID string) ([]QuoteRevisionResponse, error) {
+	if _, err := s.findOwned(userID, productID); err != nil {
+		return nil, err
+	}
+
+	quotes, err := s.repo.FindQuoteRevisions(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]QuoteRevisionResponse, 0, len(quotes))
+	for _, q := range quotes {
+		res = append(res, mapQuoteToResponse(q))
+	}
+	return res, nil
+}
+
+func (s *service) GetPublicQuote(token string) (*PublicQuoteResponse, error) {
+	quote, product, err := s.findQuote(token)
+	if err != nil {
+		return nil, err
+	}
+	return s.publicQuote(*quote, *product), nil
+}
+
+// AcceptQuote promotes the revision's pricing to the order and records when
+// the customer accepted it.
+func (s *service) AcceptQuote(token string) (*PublicQuoteResponse, error) {
+	quote, product, err := s.findQuote(token)
+	if err != nil {
+		return nil, err
+	}
+	if !quote.Open() || product.Status == StatusCancelled {
+		return nil, ErrQuoteClosed
+	}
+
+	now := time.Now()
+	quote.Status = QuoteAccepted
+	quote.RespondedAt = &now
+
+	quote.breakdown().apply(product)
+	product.ProfitPercentage = quote.ProfitPercentage
+	product.IncludeFixedExpenses = quote.IncludeFixedExpenses
+	product.FixedExpenseRate = quote.FixedExpenseRate
+	product.TaxRate = quote.TaxRate
+	product.TaxInclusive = quote.TaxInclusive
+	product.AcceptedQuoteID = &quote.ID
+	product.QuoteAcceptedAt = &now
+
+	// The order takes the quoted adjustments along with their amounts
+	var adjustments []ProductAdjustment
+	if quote.Adjustments != nil {
+		adjustments = newAdjustments(quote.Adjustments)
+	}
+
+	if err := s.repo.AcceptQuoteRevision(quote, product, adjustments); err != nil {
+		return nil, err
+	}
+	return s.publicQuote(*quote, *product), nil
+}
+
+func (s *service) DeclineQuote(token string, req DeclineQuoteRequest) (*PublicQuoteResponse, error) {
+	quote, product, err := s.findQuote(token)
+	if err != nil {
+		return nil, err
+	}
+	if !quote.Open() {
+		return nil, ErrQuoteClosed
+	}
+
+	now := time.Now()
+	quote.Status = QuoteDeclined
+	quote.RespondedAt = &now
+	quote.DeclineReason = req.Reason
+
+	if err := s.repo.UpdateQuoteRevision(quote); err != nil {
+		return nil, err
+	}
+	return s.publicQuote(*quote, *product), nil
+}
+
+// findQuote returns the revision behind an approval link with its product.
+func (s *service) findQuote(token string) (*QuoteRevision, *Product, error) {
+	quote, err := s.repo.FindQuoteRevisionByToken(token)
+	if err != nil {
+		return nil, nil, ErrQuoteNotFound
+	}
+	product, err := s.repo.FindByID(quote.ProductID.String())
+	if err != nil {
+		return nil, nil, ErrQuoteNotFound
+	}
+	return quote, product, nil
+}
+
+func (s *service) publicQuote(q QuoteRevision, p Product) *PublicQuoteResponse {
+	// The bill of materials is only itemized while it matches the quoted cost
+	quoted := p
+	q.breakdown().apply(&quoted)
+	quoted.ProfitPercentage = q.ProfitPercentage
+	quoted.Adjustments = newAdjustments(q.Adjustments)
+	if materialsCost(p.Materials).Sub(q.MaterialsCost).Abs().GreaterThan(priceTolerance) {
+		quoted.Materials = nil
+	}
+
+	res := &PublicQuoteResponse{
+		Token:       q.Token,
+		Name:        p.Name,
+		Number:      q.Number,
+		Status:      quoteStatus(q),
+		Open:        q.Open() && p.Status != StatusCancelled,
+		Subtotal:    q.Total.Sub(q.TaxAmount),
+		TaxRate:     q.TaxRate,
+		TaxAmount:   q.TaxAmount,
+		Total:       q.Total,
+		Currency:    p.Currency,
+		Note:        q.Note,
+		DueDate:     formatDate(p.DueDate),
+		ExpiresAt:   formatTimestamp(q.ExpiresAt),
+		RespondedAt: formatTimestamp(q.RespondedAt),
+	}
+	res.Workshop, res.Locale = s.workshop(p.UserID)
+	for _, l := range documentLines(quoted) {
+		res.Lines = append(res.Lines, mapPublicQuoteLine(l))
+	}
+	res.Adjustments = []PublicQuoteLineResponse{}
+	for _, l := range adjustmentLines(quoted) {
+		res.Adjustments = append(res.Adjustments, mapPublicQuoteLine(l))
+	}
+	return res
+}
+
+func mapPublicQuoteLine(l documentLine) PublicQuoteLineResponse {
+	return PublicQuoteLineResponse{
+		Description: l.Description,
+		Quantity:    l.Quantity,
+		Unit:        l.Unit,
+		UnitPrice:   l.UnitPrice,
+		Amount:      l.Amount,
+	}
+}
+
+func (q QuoteRevision) breakdown() PriceBreakdown {
+	return PriceBreakdown{
+		MaterialsCost:       q.MaterialsCost,
+		HoursCost:           q.HoursCost,
+		Subtotal:            q.Subtotal,
+		FixedExpensesAmount: q.FixedExpensesAmount,
+		BaseTotal:           q.BaseTotal,
+		ProfitAmount:        q.ProfitAmount,
+		DiscountAmount:      q.DiscountAmount,
+		SurchargeAmount:     q.SurchargeAmount,
+		TaxAmount:           q.TaxAmount,
+		Total:               q.Total,
+	}
+}
+
+// quoteStatus reports pending revisions past their expiry as expired.
+func quoteStatus(q QuoteRevision) string {
+	if q.Status == QuotePending && !q.Open() {
+		return QuoteExpired
+	}
+	return q.Status
+}
+
+func formatTimestamp(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	s := t.Format("2006-01-02 15:04:05")
+	return &s
+}
+
+func mapQuoteToResponse(q QuoteRevision) QuoteRevisionResponse {
+	return QuoteRevisionResponse{
+		ID:                   q.ID.String(),
+		ProductID:            q.ProductID.String(),
+		Number:               q.Number,
+		Status:               quoteStatus(q),
+		ProfitPercentage:     q.ProfitPercentage,
+		IncludeFixedExpenses: q.IncludeFixedExpenses,
+		FixedExpenseRate:     q.FixedExpenseRate,
+		TaxRate:              q.TaxRate,
+		TaxInclusive:         q.TaxInclusive,
+		Pricing:              mapBreakdownToResponse(q.breakdown()),
+		Note:                 q.Note,
+		ApprovalURL:          publicURL("quotes/" + q.Token),
+		ExpiresAt:            formatTimestamp(q.ExpiresAt),
+		RespondedAt:          formatTimestamp(q.RespondedAt),
+		DeclineReason:        q.DeclineReason,
+		CreatedAt:            q.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
@@ -1,6 +1,15 @@
 package products
 
-import "gorm.io/gorm"
+import (
+	"fmt"
+	"time"
+
+	"github.com/TFX0019/api-go-gds/features/materials"
+	"github.com/TFX0019/api-go-gds/pkg/utils"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
 
 type Repository interface {
 	Create(product *Product) error
@@ -8,13 +17,45 @@ type Repository interface {
 	FindByID(id string) (*Product, error)
 	FindByUserID(userID string, limit, offset int) ([]Product, int64, error)
 	CountByUserID(userID uint) (int64, error)
-	GetProfitLoss(userID string, month int) (*ProfitLossResponse, error)
+	GetProfitLoss(userID string, from, to *time.Time, groupBy string) ([]ProfitLossRow, error)
+	GetTaxReport(userID string, from, to time.Time) ([]TaxReportRow, error)
 	Update(product *Product) error
+	SaveWithMaterials(product *Product, items []ProductMaterial, adjustments []ProductAdjustment, stock map[uuid.UUID]float64) error
+	ChangeStatus(product *Product, stock map[uuid.UUID]float64, entry *ProductStatusHistory, payment *Payment) error
+	FindStatusHistory(productID string) ([]ProductStatusHistory, error)
 	Delete(id string) error
 	AddImage(image *ProductImage) error
 	DeleteImage(id string) error
 	CountImages(productID string) (int64, error)
 	GetImageByID(id string) (*ProductImage, error)
+	CreateTimeEntry(entry *TimeEntry) error
+	FindTimeEntries(productID string) ([]TimeEntry, error)
+	FindTimeEntryByID(id string) (*TimeEntry, error)
+	FindRunningTimeEntry(productID string, userID uint) (*TimeEntry, error)
+	UpdateTimeEntry(entry *TimeEntry) error
+	DeleteTimeEntry(id string) error
+	FindTaskProductID(taskID string, userID uint) (*uuid.UUID, error)
+	FindDue(userID string, from, to *time.Time, limit, offset int) ([]Product, int64, error)
+	AddFitting(fitting *ProductFitting) error
+	FindFittingByID(id string) (*ProductFitting, error)
+	DeleteFitting(id string) error
+	FindPayments(productID string) ([]Payment, error)
+	FindPaymentByID(id string) (*Payment, error)
+	AddPayment(productID uuid.UUID, payment *Payment, settle func(product *Product) (*ProductStatusHistory, error)) error
+	DeletePayment(product *Product, id string) error
+	CreateDocument(doc *Document, render func(doc *Document) error) error
+	FindDocuments(productID string) ([]Document, error)
+	FindDocumentByID(id string) (*Document, error)
+	CreateQuoteRevision(quote *QuoteRevision) error
+	FindQuoteRevisions(productID string) ([]QuoteRevision, error)
+	FindQuoteRevisionByToken(token string) (*QuoteRevision, error)
+	UpdateQuoteRevision(quote *QuoteRevision) error
+	AcceptQuoteRevision(quote *QuoteRevision, product *Product, adjustments []ProductAdjustment) error
+	CreateShareLink(link *ProductShareLink) error
+	FindShareLinks(productID string) ([]ProductShareLink, error)
+	FindShareLinkByID(id string) (*ProductShareLink, error)
+	FindShareLinkByToken(token string) (*ProductShareLink, error)
+	UpdateShareLink(link *ProductShareLink) error
 }
 
 type repository struct {
@@ -38,7 +79,7 @@ func (r *repository) FindAll(limit, offset int) ([]Product, int64, error) {
 		return nil, 0, err
 	}
 
-	err = r.db.Preload("Images").Limit(limit).Offset(offset).Order("created_at desc").Find(&products).Error
+	err = r.db.Preload("Images").Preload("Payments").Limit(limit).Offset(offset).Order("created_at desc").Find(&products).Error
 	if err != nil {
 		return nil, 0, err
 	}
@@ -48,7 +89,10 @@ func (r *repository) FindAll(limit, offset int) ([]Product, int64, error) {
 
 func (r *repository) FindByID(id string) (*Product, error) {
 	var product Product
-	err := r.db.Preload("Images").Where("id = ?", id).First(&product).Error
+	err := r.db.Preload("Images").Preload("Materials.Material").Preload("Payments").
+		Preload("Adjustments", func(db *gorm.DB) *gorm.DB { return db.Order("position asc") }).
+		Preload("Fittings", func(db *gorm.DB) *gorm.DB { return db.Order("scheduled_at asc") }).
+		Where("id = ?", id).First(&product).Error
 	if err != nil {
 		return nil, err
 	}
@@ -64,7 +108,7 @@ func (r *repository) FindByUserID(userID string, limit, offset int) ([]Product,
 		return nil, 0, err
 	}
 
-	err = r.db.Preload("Images").Where("user_id = ?", userID).Limit(limit).Offset(offset).Order("created_at desc").Find(&products).Error
+	err = r.db.Preload("Images").Preload("Payments").Where("user_id = ?", userID).Limit(limit).Offset(offset).Order("created_at desc").Find(&products).Error
 	if err != nil {
 		return nil, 0, err
 	}
@@ -72,30 +116,272 @@ func (r *repository) FindByUserID(userID string, limit, offset int) ([]Product,
 	return products, total, nil
 }
 
+// UndeliveredCondition matches the orders the customer has not received
+// yet. Orders can be paid before being delivered, so paid ones count until
+// they have been through delivered.
+const UndeliveredCondition = `(products.status IN ('pending', 'in_process', 'complete') OR
+	(products.status = 'paid' AND NOT EXISTS (
+		SELECT 1 FROM product_status_history h WHERE h.product_id = products.id AND h.to_status = 'delivered')))`
+
+// FindDue returns the user's undelivered orders due between from and to,
+// both optional and inclusive, soonest first.
+func (r *repository) FindDue(userID string, from, to *time.Time, limit, offset int) ([]Product, int64, error) {
+	query := r.db.Model(&Product{}).Where("products.user_id = ? AND products.due_date IS NOT NULL", userID).Where(UndeliveredCondition)
+	if from != nil {
+		query = query.Where("products.due_date >= ?", *from)
+	}
+	if to != nil {
+		query = query.Where("products.due_date <= ?", *to)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var products []Product
+	err := query.Preload("Images").Preload("Payments").
+		Preload("Fittings", func(db *gorm.DB) *gorm.DB { return db.Order("scheduled_at asc") }).
+		Limit(limit).Offset(offset).Order("products.due_date asc, products.created_at asc").Find(&products).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return products, total, nil
+}
+
+func (r *repository) AddFitting(fitting *ProductFitting) error {
+	return r.db.Create(fitting).Error
+}
+
+func (r *repository) FindFittingByID(id string) (*ProductFitting, error) {
+	var fitting ProductFitting
+	if err := r.db.First(&fitting, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &fitting, nil
+}
+
+func (r *repository) DeleteFitting(id string) error {
+	return r.db.Delete(&ProductFitting{}, "id = ?", id).Error
+}
+
 func (r *repository) CountByUserID(userID uint) (int64, error) {
 	var total int64
 	err := r.db.Model(&Product{}).Where("user_id = ?", userID).Count(&total).Error
 	return total, err
 }
 
-func (r *repository) GetProfitLoss(userID string, month int) (*ProfitLossResponse, error) {
-	var result ProfitLossResponse
-	query := r.db.Model(&Product{}).Where("user_id = ? AND status = ?", userID, "paid")
+// ProfitLossRow holds the P&L amounts of the orders priced in one currency,
+// for one group when the report is grouped.
+type ProfitLossRow struct {
+	GroupKey                 string
+	GroupLabel               string
+	Currency                 string
+	OrderCount               int64
+	TotalMaterialsCost       utils.Money
+	TotalHoursCost           utils.Money
+	TotalFixedExpensesAmount utils.Money
+	TotalProfitAmount        utils.Money
+	TotalDiscountAmount      utils.Money
+	TotalSurchargeAmount     utils.Money
+	TotalTaxAmount           utils.Money
+	TotalReceived            utils.Money
+}
+
+// profitLossGroups are the SQL key and label of each way of grouping the
+// P&L report.
+var profitLossGroups = map[string][2]string{
+	"":         {"''", "''"},
+	"month":    {"to_char(product_payments.paid_at, 'YYYY-MM')", "MAX(to_char(product_payments.paid_at, 'YYYY-MM'))"},
+	"week":     {"to_char(date_trunc('week', product_payments.paid_at), 'YYYY-MM-DD')", `MAX(to_char(product_payments.paid_at, 'IYYY-"W"IW'))`},
+	"customer": {"COALESCE(products.client_id::text, '')", "COALESCE(MAX(customers.name), '')"},
+}
 
-	if month > 0 {
-		query = query.Where("EXTRACT(MONTH FROM date_paid) = ? AND EXTRACT(YEAR FROM date_paid) = EXTRACT(YEAR FROM CURRENT_DATE)", month)
+// GetProfitLoss splits each payment received between from and to, both
+// optional and included, between the costs and profit of its order in
+// proportion to the order's total. It returns one row per currency, and per
+// group when groupBy is month, week or customer.
+func (r *repository) GetProfitLoss(userID string, from, to *time.Time, groupBy string) ([]ProfitLossRow, error) {
+	group, ok := profitLossGroups[groupBy]
+	if !ok {
+		return nil, fmt.Errorf("unknown grouping %q", groupBy)
 	}
 
-	err := query.Select("COALESCE(SUM(materials_cost), 0) as total_materials_cost, COALESCE(SUM(hours_cost), 0) as total_hours_cost, COALESCE(SUM(fixed_expenses_amount), 0) as total_fixed_expenses_amount, COALESCE(SUM(profit_amount), 0) as total_profit_amount").
-		Scan(&result).Error
+	var rows []ProfitLossRow
+	query := r.db.Table("product_payments").
+		Joins("JOIN products ON products.id = product_payments.product_id").
+		Where("products.user_id = ? AND products.status <> ?", userID, StatusCancelled)
+	if groupBy == "customer" {
+		query = query.Joins("LEFT JOIN customers ON customers.id = products.client_id")
+	}
+	if from != nil {
+		query = query.Where("product_payments.paid_at >= ?", *from)
+	}
+	if to != nil {
+		query = query.Where("product_payments.paid_at < ?", to.AddDate(0, 0, 1))
+	}
+
+	share := "product_payments.amount / NULLIF(products.total, 0)"
+	err := query.Select(group[0] + " as group_key, " + group[1] + " as group_label, products.currency as currency, " +
+		"COUNT(DISTINCT products.id) as order_count, " +
+		"COALESCE(SUM(" + share + " * products.materials_cost), 0) as total_materials_cost, " +
+		"COALESCE(SUM(" + share + " * products.hours_cost), 0) as total_hours_cost, " +
+		"COALESCE(SUM(" + share + " * products.fixed_expenses_amount), 0) as total_fixed_expenses_amount, " +
+		"COALESCE(SUM(" + share + " * products.profit_amount), 0) as total_profit_amount, " +
+		"COALESCE(SUM(" + share + " * products.discount_amount), 0) as total_discount_amount, " +
+		"COALESCE(SUM(" + share + " * products.surcharge_amount), 0) as total_surcharge_amount, " +
+		"COALESCE(SUM(" + share + " * products.tax_amount), 0) as total_tax_amount, " +
+		"COALESCE(SUM(product_payments.amount), 0) as total_received").
+		Group("group_key, products.currency").
+		Order("group_key").
+		Scan(&rows).Error
 	if err != nil {
 		return nil, err
 	}
-	return &result, nil
+	return rows, nil
+}
+
+// TaxReportRow holds the payments received in a month for the orders priced
+// in one currency, with the tax they carried.
+type TaxReportRow struct {
+	Period        string
+	Currency      string
+	TaxCollected  utils.Money
+	TotalReceived utils.Money
 }
 
+// GetTaxReport splits each payment received between from and to, both
+// included, into the tax of its order in proportion to the order's total,
+// with one row per month and currency.
+func (r *repository) GetTaxReport(userID string, from, to time.Time) ([]TaxReportRow, error) {
+	var rows []TaxReportRow
+	share := "product_payments.amount / NULLIF(products.total, 0)"
+	err := r.db.Table("product_payments").
+		Joins("JOIN products ON products.id = product_payments.product_id").
+		Where("products.user_id = ? AND products.status <> ?", userID, StatusCancelled).
+		Where("product_payments.paid_at >= ? AND product_payments.paid_at < ?", from, to.AddDate(0, 0, 1)).
+		Select("to_char(product_payments.paid_at, 'YYYY-MM') as period, products.currency as currency, " +
+			"COALESCE(SUM(" + share + " * products.tax_amount), 0) as tax_collected, " +
+			"COALESCE(SUM(product_payments.amount), 0) as total_received").
+		Group("period, products.currency").
+		Order("period").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// Update saves the product's own fields; materials and payments have their
+// own methods.
 func (r *repository) Update(product *Product) error {
-	return r.db.Save(product).Error
+	return r.db.Omit("Materials", "Payments", "Fittings", "Adjustments").Save(product).Error
+}
+
+// SaveWithMaterials saves the product, replaces its bill of materials when
+// items is not nil and its adjustments when adjustments is not nil, and
+// moves the materials' stock by the given amounts.
+func (r *repository) SaveWithMaterials(product *Product, items []ProductMaterial, adjustments []ProductAdjustment, stock map[uuid.UUID]float64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Materials", "Payments", "Fittings", "Adjustments").Save(product).Error; err != nil {
+			return err
+		}
+
+		if items != nil {
+			if err := tx.Where("product_id = ?", product.ID).Delete(&ProductMaterial{}).Error; err != nil {
+				return err
+			}
+			for i := range items {
+				items[i].ProductID = product.ID
+			}
+			if len(items) > 0 {
+				if err := tx.Omit("Material").Create(&items).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := replaceAdjustments(tx, product.ID, adjustments); err != nil {
+			return err
+		}
+
+		return moveStock(tx, product, stock)
+	})
+}
+
+// replaceAdjustments swaps the product's adjustments for the given ones; nil
+// keeps the current ones.
+func replaceAdjustments(tx *gorm.DB, productID uuid.UUID, adjustments []ProductAdjustment) error {
+	if adjustments == nil {
+		return nil
+	}
+	if err := tx.Where("product_id = ?", productID).Delete(&ProductAdjustment{}).Error; err != nil {
+		return err
+	}
+	for i := range adjustments {
+		adjustments[i].ProductID = productID
+	}
+	if len(adjustments) == 0 {
+		return nil
+	}
+	return tx.Create(&adjustments).Error
+}
+
+// ChangeStatus saves the product's new status along with its history entry,
+// the stock it moves and, when not nil, the payment settling the order.
+func (r *repository) ChangeStatus(product *Product, stock map[uuid.UUID]float64, entry *ProductStatusHistory, payment *Payment) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Materials", "Payments", "Fittings", "Adjustments").Save(product).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(entry).Error; err != nil {
+			return err
+		}
+		if payment != nil {
+			if err := tx.Create(payment).Error; err != nil {
+				return err
+			}
+		}
+		return moveStock(tx, product, stock)
+	})
+}
+
+// moveStock records the order's consumption in the materials' stock ledger.
+// Materials given back to the stock are recorded as negative consumption.
+func moveStock(tx *gorm.DB, product *Product, stock map[uuid.UUID]float64) error {
+	now := time.Now()
+	productID := product.ID
+	for materialID, change := range stock {
+		if change == 0 {
+			continue
+		}
+		note := "Used by " + product.Name
+		if change > 0 {
+			note = "Returned from " + product.Name
+		}
+		err := materials.RecordMovement(tx, &materials.StockMovement{
+			MaterialID: materialID,
+			UserID:     product.UserID,
+			Type:       materials.MovementConsumption,
+			Quantity:   change,
+			ProductID:  &productID,
+			Note:       note,
+			OccurredAt: now,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *repository) FindStatusHistory(productID string) ([]ProductStatusHistory, error) {
+	var history []ProductStatusHistory
+	err := r.db.Where("product_id = ?", productID).Order("created_at asc").Find(&history).Error
+	if err != nil {
+		return nil, err
+	}
+	return history, nil
 }
 
 func (r *repository) Delete(id string) error {
@@ -124,3 +410,254 @@ func (r *repository) GetImageByID(id string) (*ProductImage, error) {
 	}
 	return &image, nil
 }
+
+func (r *repository) CreateTimeEntry(entry *TimeEntry) error {
+	return r.db.Create(entry).Error
+}
+
+func (r *repository) FindTimeEntries(productID string) ([]TimeEntry, error) {
+	var entries []TimeEntry
+	err := r.db.Where("product_id = ?", productID).Order("started_at desc").Find(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *repository) FindTimeEntryByID(id string) (*TimeEntry, error) {
+	var entry TimeEntry
+	err := r.db.Where("id = ?", id).First(&entry).Error
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *repository) FindRunningTimeEntry(productID string, userID uint) (*TimeEntry, error) {
+	var entry TimeEntry
+	err := r.db.Where("product_id = ? AND user_id = ? AND ended_at IS NULL", productID, userID).First(&entry).Error
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *repository) UpdateTimeEntry(entry *TimeEntry) error {
+	return r.db.Save(entry).Error
+}
+
+func (r *repository) DeleteTimeEntry(id string) error {
+	return r.db.Delete(&TimeEntry{}, "id = ?", id).Error
+}
+
+// FindTaskProductID returns the product a task of the user is linked to. The
+// tasks table is read directly since the tasks package depends on this one.
+func (r *repository) FindTaskProductID(taskID string, userID uint) (*uuid.UUID, error) {
+	var task struct {
+		ProductID *uuid.UUID
+	}
+	err := r.db.Table("tasks").Select("product_id").Where("id = ? AND user_id = ?", taskID, userID).Take(&task).Error
+	if err != nil {
+		return nil, err
+	}
+	return task.ProductID, nil
+}
+
+func (r *repository) FindPayments(productID string) ([]Payment, error) {
+	var payments []Payment
+	err := r.db.Where("product_id = ?", productID).Order("paid_at asc, created_at asc").Find(&payments).Error
+	if err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
+
+func (r *repository) FindPaymentByID(id string) (*Payment, error) {
+	var payment Payment
+	err := r.db.Where("id = ?", id).First(&payment).Error
+	if err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+// AddPayment locks the product and hands it with its current payments to
+// settle, which checks the balance and settles the order, so concurrent
+// payments cannot overpay it. The payment is then recorded and the product
+// saved, along with the history entry settle returns.
+func (r *repository) AddPayment(productID uuid.UUID, payment *Payment, settle func(product *Product) (*ProductStatusHistory, error)) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var product Product
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", productID).First(&product).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Where("product_id = ?", productID).Find(&product.Payments).Error; err != nil {
+			return err
+		}
+
+		entry, err := settle(&product)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Create(payment).Error; err != nil {
+			return err
+		}
+		if err := tx.Omit("Materials", "Payments", "Fittings", "Adjustments").Save(&product).Error; err != nil {
+			return err
+		}
+		if entry != nil {
+			return tx.Create(entry).Error
+		}
+		return nil
+	})
+}
+
+func (r *repository) DeletePayment(product *Product, id string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&Payment{}, "id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Omit("Materials", "Payments", "Fittings", "Adjustments").Save(product).Error
+	})
+}
+
+// CreateDocument gives the document the user's next number for its type and
+// saves it once render has written the file. A failed render or insert does
+// not use up the number; the caller removes the file it rendered.
+func (r *repository) CreateDocument(doc *Document, render func(doc *Document) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var sequence DocumentSequence
+		err := tx.Raw(`INSERT INTO document_sequences (user_id, type, last_number) VALUES (?, ?, 1)
+			ON CONFLICT (user_id, type) DO UPDATE SET last_number = document_sequences.last_number + 1
+			RETURNING user_id, type, last_number`, doc.UserID, doc.Type).Scan(&sequence).Error
+		if err != nil {
+			return err
+		}
+		doc.Number = sequence.LastNumber
+
+		if err := render(doc); err != nil {
+			return err
+		}
+		return tx.Create(doc).Error
+	})
+}
+
+func (r *repository) FindDocuments(productID string) ([]Document, error) {
+	var docs []Document
+	err := r.db.Where("product_id = ?", productID).Order("created_at desc").Find(&docs).Error
+	if err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+func (r *repository) FindDocumentByID(id string) (*Document, error) {
+	var doc Document
+	err := r.db.Where("id = ?", id).First(&doc).Error
+	if err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// CreateQuoteRevision numbers the revision after the product's last one and
+// supersedes the revisions still waiting for an answer.
+func (r *repository) CreateQuoteRevision(quote *QuoteRevision) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var last int
+		err := tx.Model(&QuoteRevision{}).Where("product_id = ?", quote.ProductID).
+			Select("COALESCE(MAX(number), 0)").Scan(&last).Error
+		if err != nil {
+			return err
+		}
+		quote.Number = last + 1
+
+		if err := supersedeQuotes(tx, quote.ProductID); err != nil {
+			return err
+		}
+		return tx.Create(quote).Error
+	})
+}
+
+func (r *repository) FindQuoteRevisions(productID string) ([]QuoteRevision, error) {
+	var quotes []QuoteRevision
+	err := r.db.Where("product_id = ?", productID).Order("number desc").Find(&quotes).Error
+	if err != nil {
+		return nil, err
+	}
+	return quotes, nil
+}
+
+func (r *repository) FindQuoteRevisionByToken(token string) (*QuoteRevision, error) {
+	var quote QuoteRevision
+	err := r.db.Where("token = ?", token).First(&quote).Error
+	if err != nil {
+		return nil, err
+	}
+	return &quote, nil
+}
+
+func (r *repository) UpdateQuoteRevision(quote *QuoteRevision) error {
+	return r.db.Save(quote).Error
+}
+
+// AcceptQuoteRevision saves the accepted revision along with the product
+// repriced from it and its quoted adjustments, superseding any other open
+// revision.
+func (r *repository) AcceptQuoteRevision(quote *QuoteRevision, product *Product, adjustments []ProductAdjustment) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(quote).Error; err != nil {
+			return err
+		}
+		if err := supersedeQuotes(tx, quote.ProductID); err != nil {
+			return err
+		}
+		if err := tx.Omit("Materials", "Payments", "Fittings", "Adjustments").Save(product).Error; err != nil {
+			return err
+		}
+		return replaceAdjustments(tx, product.ID, adjustments)
+	})
+}
+
+func supersedeQuotes(tx *gorm.DB, productID uuid.UUID) error {
+	return tx.Model(&QuoteRevision{}).
+		Where("product_id = ? AND status = ?", productID, QuotePending).
+		Update("status", QuoteSuperseded).Error
+}
+
+func (r *repository) CreateShareLink(link *ProductShareLink) error {
+	return r.db.Create(link).Error
+}
+
+func (r *repository) FindShareLinks(productID string) ([]ProductShareLink, error) {
+	var links []ProductShareLink
+	err := r.db.Where("product_id = ?", productID).Order("created_at desc").Find(&links).Error
+	if err != nil {
+		return nil, err
+	}
+	return links, nil
+}
+
+func (r *repository) FindShareLinkByID(id string) (*ProductShareLink, error) {
+	var link ProductShareLink
+	err := r.db.Where("id = ?", id).First(&link).Error
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+func (r *repository) FindShareLinkByToken(token string) (*ProductShareLink, error) {
+	var link ProductShareLink
+	err := r.db.Where("token = ?", token).First(&link).Error
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+func (r *repository) UpdateShareLink(link *ProductShareLink) error {
+	return r.db.Save(link).Error
+}
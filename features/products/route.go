@@ -11,11 +11,50 @@ func RegisterRoutes(app fiber.Router, controller *Controller) {
 	route.Post("/", controller.Create)
 	route.Get("/", controller.GetAll)
 	route.Get("/user", controller.GetByUserID)
+	route.Get("/upcoming", controller.GetUpcoming)
+	route.Get("/overdue", controller.GetOverdue)
 	route.Get("/profit-loss", controller.GetProfitLoss)
+	route.Get("/profit-loss/export", controller.ExportProfitLoss)
+	route.Get("/tax-report", controller.GetTaxReport)
+	route.Post("/pricing/preview", controller.PreviewPrice)
 	route.Get("/:id", controller.GetByID)
 	route.Put("/:id", controller.Update)
 	route.Patch("/:id/status", controller.UpdateStatus)
+	route.Get("/:id/status-history", controller.GetStatusHistory)
+	route.Get("/:id/stock-check", controller.CheckStock)
 	route.Delete("/:id", controller.Delete)
 	route.Post("/:id/images", controller.UploadImages)
 	route.Delete("/:id/images/:image_id", controller.DeleteImage)
+	route.Get("/:id/time-entries", controller.GetTimeTracking)
+	route.Post("/:id/time-entries", controller.AddTimeEntry)
+	route.Post("/:id/time-entries/start", controller.StartTimer)
+	route.Post("/:id/time-entries/stop", controller.StopTimer)
+	route.Delete("/:id/time-entries/:entry_id", controller.DeleteTimeEntry)
+	route.Post("/:id/fittings", controller.AddFitting)
+	route.Delete("/:id/fittings/:fitting_id", controller.DeleteFitting)
+	route.Get("/:id/payments", controller.GetPayments)
+	route.Post("/:id/payments", controller.AddPayment)
+	route.Delete("/:id/payments/:payment_id", controller.DeletePayment)
+	route.Get("/:id/documents", controller.GetDocuments)
+	route.Post("/:id/documents", controller.CreateDocument)
+	route.Get("/:id/documents/:document_id/download", controller.DownloadDocument)
+	route.Get("/:id/quotes", controller.GetQuoteRevisions)
+	route.Post("/:id/quotes", controller.CreateQuoteRevision)
+	route.Get("/:id/share-links", controller.GetShareLinks)
+	route.Post("/:id/share-links", controller.CreateShareLink)
+	route.Delete("/:id/share-links/:link_id", controller.RevokeShareLink)
+}
+
+// RegisterPublicRoutes exposes the orders behind share links and the quotes
+// behind approval links without a login.
+func RegisterPublicRoutes(app fiber.Router, controller *Controller) {
+	app.Get("/api/public/orders/:token", controller.GetPublicOrder)
+	app.Get("/orders/:token", controller.PublicOrderPage)
+
+	app.Get("/api/public/quotes/:token", controller.GetPublicQuote)
+	app.Post("/api/public/quotes/:token/accept", controller.AcceptQuote)
+	app.Post("/api/public/quotes/:token/decline", controller.DeclineQuote)
+	app.Get("/quotes/:token", controller.PublicQuotePage)
+	app.Post("/quotes/:token/accept", controller.AcceptQuotePage)
+	app.Post("/quotes/:token/decline", controller.DeclineQuotePage)
 }
@@ -5,11 +5,18 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/TFX0019/api-go-gds/features/auth"
+	"github.com/TFX0019/api-go-gds/features/customers"
+	"github.com/TFX0019/api-go-gds/features/exchange_rates"
+	"github.com/TFX0019/api-go-gds/features/materials"
 	"github.com/TFX0019/api-go-gds/features/plans"
+	"github.com/TFX0019/api-go-gds/features/tax_profiles"
+	"github.com/TFX0019/api-go-gds/pkg/utils"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type Service interface {
@@ -17,22 +24,55 @@ type Service interface {
 	GetAll(page, limit int) (*PaginatedResponse, error)
 	GetByID(id string) (*ProductResponse, error)
 	GetByUserID(userID string, page, limit int) (*PaginatedResponse, error)
-	GetProfitLoss(userID string, month int) (*ProfitLossResponse, error)
+	GetUpcoming(userID string, days, page, limit int) (*PaginatedResponse, error)
+	GetOverdue(userID string, page, limit int) (*PaginatedResponse, error)
+	GetProfitLoss(userID string, query ProfitLossQuery) (*ProfitLossResponse, error)
+	ExportProfitLoss(userID string, query ProfitLossQuery) ([]byte, error)
+	GetTaxReport(userID string, query TaxReportQuery) (*TaxReportResponse, error)
 	Update(id string, req UpdateProductRequest) (*ProductResponse, error)
-	UpdateStatus(id string, status string) (*ProductResponse, error)
+	UpdateStatus(userID, id string, req UpdateProductStatusRequest) (*ProductResponse, error)
+	GetStatusHistory(userID, productID string) ([]StatusHistoryResponse, error)
 	Delete(id string) error
 	AddImages(productID string, paths []string) ([]ProductImageResponse, error)
 	DeleteImage(imageID string, productID string) error
+	PreviewPrice(req PricingRequest) PriceBreakdownResponse
+	CheckStock(userID, id string) ([]StockWarningResponse, error)
+	GetTimeTracking(userID, productID string) (*TimeTrackingResponse, error)
+	StartTimer(userID, productID string, req StartTimerRequest) (*TimeEntryResponse, error)
+	StopTimer(userID, productID string) (*TimeEntryResponse, error)
+	AddTimeEntry(userID, productID string, req ManualTimeEntryRequest) (*TimeEntryResponse, error)
+	DeleteTimeEntry(userID, productID, entryID string) error
+	CreateShareLink(userID, productID string, req CreateShareLinkRequest) (*ShareLinkResponse, error)
+	GetShareLinks(userID, productID string) ([]ShareLinkResponse, error)
+	RevokeShareLink(userID, productID, linkID string) error
+	AddFitting(userID, productID string, req CreateFittingRequest) (*FittingResponse, error)
+	DeleteFitting(userID, productID, fittingID string) error
+	GetPayments(userID, productID string) (*PaymentsResponse, error)
+	CreateDocument(userID, productID string, req CreateDocumentRequest) (*DocumentResponse, error)
+	GetDocuments(userID, productID string) ([]DocumentResponse, error)
+	GetDocument(userID, productID, documentID string) (*Document, error)
+	AddPayment(userID, productID string, req CreatePaymentRequest) (*PaymentResponse, error)
+	DeletePayment(userID, productID, paymentID string) error
+	GetPublicOrder(token string) (*PublicOrderResponse, error)
+	CreateQuoteRevision(userID, productID string, req CreateQuoteRevisionRequest) (*QuoteRevisionResponse, error)
+	GetQuoteRevisions(userID, productID string) ([]QuoteRevisionResponse, error)
+	GetPublicQuote(token string) (*PublicQuoteResponse, error)
+	AcceptQuote(token string) (*PublicQuoteResponse, error)
+	DeclineQuote(token string, req DeclineQuoteRequest) (*PublicQuoteResponse, error)
 }
 
 type service struct {
-	repo      Repository
-	authRepo  auth.Repository
-	plansRepo plans.Repository
+	repo            Repository
+	authRepo        auth.Repository
+	plansRepo       plans.Repository
+	customersRepo   customers.Repository
+	materialsRepo   materials.Repository
+	ratesRepo       exchange_rates.Repository
+	taxProfilesRepo tax_profiles.Repository
 }
 
-func NewService(repo Repository, authRepo auth.Repository, plansRepo plans.Repository) Service {
-	return &service{repo: repo, authRepo: authRepo, plansRepo: plansRepo}
+func NewService(repo Repository, authRepo auth.Repository, plansRepo plans.Repository, customersRepo customers.Repository, materialsRepo materials.Repository, ratesRepo exchange_rates.Repository, taxProfilesRepo tax_profiles.Repository) Service {
+	return &service{repo: repo, authRepo: authRepo, plansRepo: plansRepo, customersRepo: customersRepo, materialsRepo: materialsRepo, ratesRepo: ratesRepo, taxProfilesRepo: taxProfilesRepo}
 }
 
 func (s *service) Create(userID string, req CreateProductRequest) (*ProductResponse, error) {
@@ -76,22 +116,66 @@ func (s *service) Create(userID string, req CreateProductRequest) (*ProductRespo
 		clientUUID = &id
 	}
 
+	snapshotUUID, err := s.resolveMeasurementSnapshot(clientUUID, req.MeasurementSnapshotID)
+	if err != nil {
+		return nil, err
+	}
+
+	dueDate, err := parseDate(req.DueDate)
+	if err != nil {
+		return nil, err
+	}
+
+	currency := user.Currency
+	if currency == "" {
+		currency = utils.DefaultCurrency
+	}
+
+	items, err := s.resolveMaterials(uint(uid), currency, req.Materials)
+	if err != nil {
+		return nil, err
+	}
+
+	var taxProfile *tax_profiles.TaxProfile
+	if req.TaxProfileID == nil {
+		// No default profile means no tax
+		taxProfile, _ = s.taxProfilesRepo.FindDefault(uint(uid))
+	} else if taxProfile, err = s.resolveTaxProfile(uint(uid), *req.TaxProfileID); err != nil {
+		return nil, err
+	}
+
 	product := &Product{
-		UserID:               uint(uid),
-		Name:                 req.Name,
-		ClientID:             clientUUID,
-		MaterialsCost:        req.MaterialsCost,
-		HoursCost:            req.HoursCost,
-		ProfitPercentage:     req.ProfitPercentage,
-		IncludeFixedExpenses: req.IncludeFixedExpenses,
-		FixedExpenseRate:     req.FixedExpenseRate,
-		Subtotal:             req.Subtotal,
-		FixedExpensesAmount:  req.FixedExpensesAmount,
-		BaseTotal:            req.BaseTotal,
-		ProfitAmount:         req.ProfitAmount,
-		Total:                req.Total,
-		Status:               "pending",
+		UserID:                uint(uid),
+		Name:                  req.Name,
+		ClientID:              clientUUID,
+		MeasurementSnapshotID: snapshotUUID,
+		ProfitPercentage:      req.ProfitPercentage,
+		IncludeFixedExpenses:  req.IncludeFixedExpenses,
+		FixedExpenseRate:      req.FixedExpenseRate,
+		Currency:              currency,
+		Status:                StatusPending,
+		DueDate:               dueDate,
+		EstimatedHours:        req.EstimatedHours,
+		Materials:             items,
+		Adjustments:           newAdjustments(req.Adjustments),
+		StatusHistory:         []ProductStatusHistory{{ToStatus: StatusPending, ChangedBy: uint(uid)}},
+	}
+	for _, path := range req.Images {
+		product.Images = append(product.Images, ProductImage{Path: path})
+	}
+	product.setTax(taxProfile)
+
+	pricing, sent := req.pricing()
+	if len(items) > 0 {
+		pricing.MaterialsCost = materialsCost(items)
 	}
+	pricing.TaxRate = product.TaxRate
+	pricing.TaxInclusive = product.TaxInclusive
+	price := CalculatePrice(pricing)
+	if err := price.Verify(sent); err != nil {
+		return nil, err
+	}
+	price.apply(product)
 
 	if err := s.repo.Create(product); err != nil {
 		return nil, err
@@ -155,8 +239,43 @@ func (s *service) GetByUserID(userID string, page, limit int) (*PaginatedRespons
 	}, nil
 }
 
-func (s *service) GetProfitLoss(userID string, month int) (*ProfitLossResponse, error) {
-	return s.repo.GetProfitLoss(userID, month)
+// reportCurrency returns the currency a report is written in: the requested
+// one, or the user's own when empty.
+func (s *service) reportCurrency(userID, currency string) (string, error) {
+	if currency == "" {
+		uid, err := strconv.ParseUint(userID, 10, 32)
+		if err != nil {
+			return "", errors.New("invalid user id")
+		}
+		user, err := s.authRepo.FindByID(uint(uid))
+		if err != nil {
+			return "", errors.New("user not found")
+		}
+		currency = user.Currency
+	}
+	currency = strings.ToUpper(currency)
+	if !utils.IsCurrency(currency) {
+		return "", errors.New("unsupported currency")
+	}
+	return currency, nil
+}
+
+// converter returns a function converting amounts into currency. The
+// exchange rates are loaded the first time another currency shows up.
+func (s *service) converter(currency string) func(amount utils.Money, from string) (utils.Money, error) {
+	var rates map[string]float64
+	return func(amount utils.Money, from string) (utils.Money, error) {
+		if from == currency {
+			return amount, nil
+		}
+		if rates == nil {
+			var err error
+			if rates, err = s.ratesRepo.Rates(); err != nil {
+				return utils.Zero, err
+			}
+		}
+		return exchange_rates.Convert(rates, amount, from, currency)
+	}
 }
 
 func (s *service) Update(id string, req UpdateProductRequest) (*ProductResponse, error) {
@@ -165,7 +284,60 @@ func (s *service) Update(id string, req UpdateProductRequest) (*ProductResponse,
 		return nil, err
 	}
 
+	// nil keeps the current bill of materials
+	var items []ProductMaterial
+	if req.Materials != nil {
+		items, err = s.resolveMaterials(product.UserID, product.Currency, *req.Materials)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	billOfMaterials := product.Materials
+	if items != nil {
+		billOfMaterials = items
+	}
+
+	// nil keeps the current tax
+	if req.TaxProfileID != nil {
+		taxProfile, err := s.resolveTaxProfile(product.UserID, *req.TaxProfileID)
+		if err != nil {
+			return nil, err
+		}
+		product.setTax(taxProfile)
+	}
+
+	// nil keeps the current adjustments
+	var adjustments []ProductAdjustment
+	pricing, sent := req.pricing()
+	if req.Adjustments != nil {
+		adjustments = newAdjustments(*req.Adjustments)
+		pricing.Adjustments = *req.Adjustments
+	} else {
+		pricing.Adjustments = adjustmentRequests(product.Adjustments)
+	}
+	if len(billOfMaterials) > 0 {
+		pricing.MaterialsCost = materialsCost(billOfMaterials)
+	}
+	// Logged time replaces the hand-entered labor cost
+	if product.LoggedHours > 0 {
+		pricing.HoursCost = product.HoursCost
+	}
+	pricing.TaxRate = product.TaxRate
+	pricing.TaxInclusive = product.TaxInclusive
+	price := CalculatePrice(pricing)
+	if err := price.Verify(sent); err != nil {
+		return nil, err
+	}
+	// Once the client accepts a quote its price is kept until a new one is
+	// accepted
+	if product.AcceptedQuoteID != nil && !price.equal(product.breakdown()) {
+		return nil, ErrPriceAccepted
+	}
+
+	clientChanged := false
 	if req.ClientID != nil {
+		previous := product.ClientID
 		if *req.ClientID == "" {
 			product.ClientID = nil
 		} else {
@@ -175,53 +347,139 @@ func (s *service) Update(id string, req UpdateProductRequest) (*ProductResponse,
 			}
 			product.ClientID = &cid
 		}
+		clientChanged = (previous == nil) != (product.ClientID == nil) || (previous != nil && *previous != *product.ClientID)
+	}
+
+	// A new client means the pinned measurements no longer apply
+	if req.MeasurementSnapshotID != nil || clientChanged {
+		snapshotUUID, err := s.resolveMeasurementSnapshot(product.ClientID, req.MeasurementSnapshotID)
+		if err != nil {
+			return nil, err
+		}
+		product.MeasurementSnapshotID = snapshotUUID
+		product.MeasurementSnapshot = nil
+	}
+
+	// An empty due date clears it
+	if req.DueDate != nil {
+		dueDate, err := parseDate(req.DueDate)
+		if err != nil {
+			return nil, err
+		}
+		if !sameDate(dueDate, product.DueDate) {
+			// A new due date gets its own reminders
+			product.DueReminderSentAt = nil
+			product.OverdueNotifiedAt = nil
+		}
+		product.DueDate = dueDate
 	}
 
 	if req.Name != "" {
 		product.Name = req.Name
 	}
+	if req.EstimatedHours != nil {
+		product.EstimatedHours = *req.EstimatedHours
+	}
 
-	product.MaterialsCost = req.MaterialsCost
-	product.HoursCost = req.HoursCost
 	product.ProfitPercentage = req.ProfitPercentage
 	product.IncludeFixedExpenses = req.IncludeFixedExpenses
 	product.FixedExpenseRate = req.FixedExpenseRate
-	product.Subtotal = req.Subtotal
-	product.FixedExpensesAmount = req.FixedExpensesAmount
-	product.BaseTotal = req.BaseTotal
-	product.ProfitAmount = req.ProfitAmount
-	product.Total = req.Total
+	price.apply(product)
+
+	// Materials already taken out of stock are swapped for the new ones
+	var stock map[uuid.UUID]float64
+	if product.StockConsumed && items != nil {
+		stock = stockChanges(product.Materials, 1, nil)
+		stock = stockChanges(items, -1, stock)
+	}
 
-	if err := s.repo.Update(product); err != nil {
+	product.Materials = nil
+	if err := s.repo.SaveWithMaterials(product, items, adjustments, stock); err != nil {
 		return nil, err
 	}
 
-	res := mapToResponse(*product)
-	return &res, nil
+	return s.reload(id)
 }
 
-func (s *service) UpdateStatus(id string, status string) (*ProductResponse, error) {
-	product, err := s.repo.FindByID(id)
+func (s *service) PreviewPrice(req PricingRequest) PriceBreakdownResponse {
+	return mapBreakdownToResponse(CalculatePrice(req))
+}
+
+func (s *service) UpdateStatus(userID, id string, req UpdateProductStatusRequest) (*ProductResponse, error) {
+	product, err := s.findOwned(userID, id)
 	if err != nil {
 		return nil, err
 	}
 
-	product.Status = status
-	if status == "paid" {
-		now := time.Now()
-		product.DatePaid = &now
-	} else {
-		product.DatePaid = nil
+	previous := product.Status
+	if err := checkTransition(previous, req.Status); err != nil {
+		return nil, err
+	}
+	product.Status = req.Status
+
+	// Marking the order as paid settles what is still owed. The payment date
+	// is kept once set, even if the order is later delivered
+	var payment *Payment
+	if req.Status == StatusPaid {
+		payment = settlingPayment(*product)
+		if product.DatePaid == nil {
+			now := time.Now()
+			product.DatePaid = &now
+		}
+	}
+
+	// Materials leave the stock when work starts and come back if the order
+	// is cancelled
+	var stock map[uuid.UUID]float64
+	switch {
+	case req.Status == StatusInProcess && !product.StockConsumed:
+		stock = stockChanges(product.Materials, -1, nil)
+		product.StockConsumed = true
+	case req.Status == StatusCancelled && product.StockConsumed:
+		stock = stockChanges(product.Materials, 1, nil)
+		product.StockConsumed = false
 	}
 
-	if err := s.repo.Update(product); err != nil {
+	entry := &ProductStatusHistory{
+		ProductID:  product.ID,
+		FromStatus: previous,
+		ToStatus:   req.Status,
+		ChangedBy:  product.UserID,
+		Note:       req.Note,
+	}
+
+	product.Materials = nil
+	product.Payments = nil
+	if err := s.repo.ChangeStatus(product, stock, entry, payment); err != nil {
 		return nil, err
 	}
 
+	return s.reload(id)
+}
+
+// reload returns the saved product with its materials' current stock.
+func (s *service) reload(id string) (*ProductResponse, error) {
+	product, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
 	res := mapToResponse(*product)
 	return &res, nil
 }
 
+func (s *service) CheckStock(userID, id string) ([]StockWarningResponse, error) {
+	product, err := s.findOwned(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	warnings := stockWarnings(*product)
+	if warnings == nil {
+		warnings = []StockWarningResponse{}
+	}
+	return warnings, nil
+}
+
 func (s *service) Delete(id string) error {
 	return s.repo.Delete(id)
 }
@@ -280,6 +538,62 @@ func (s *service) DeleteImage(imageID string, productID string) error {
 	return nil
 }
 
+// resolveMeasurementSnapshot validates the snapshot requested for a product
+// against its client. Without an explicit snapshot the client's latest
+// measurements are pinned; an empty id unpins them.
+func (s *service) resolveMeasurementSnapshot(clientID *uuid.UUID, snapshotID *string) (*uuid.UUID, error) {
+	if snapshotID != nil && *snapshotID == "" {
+		return nil, nil
+	}
+
+	if clientID == nil {
+		if snapshotID != nil {
+			return nil, errors.New("measurement snapshot requires a client")
+		}
+		return nil, nil
+	}
+
+	if snapshotID == nil {
+		latest, err := s.customersRepo.FindLatestMeasurementSnapshot(clientID.String())
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// The client has no measurements yet
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &latest.ID, nil
+	}
+
+	snapshot, err := s.customersRepo.FindMeasurementSnapshotByID(*snapshotID)
+	if err != nil {
+		return nil, errors.New("measurement snapshot not found")
+	}
+	if snapshot.CustomerID != *clientID {
+		return nil, errors.New("measurement snapshot does not belong to this client")
+	}
+	return &snapshot.ID, nil
+}
+
+func parseDate(value *string) (*time.Time, error) {
+	if value == nil || *value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse("2006-01-02", *value)
+	if err != nil {
+		return nil, errors.New("dates must use the YYYY-MM-DD format")
+	}
+	return &t, nil
+}
+
+func formatDate(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	s := t.Format("2006-01-02")
+	return &s
+}
+
 func mapToResponse(p Product) ProductResponse {
 	var cid *string
 	if p.ClientID != nil {
@@ -287,12 +601,34 @@ func mapToResponse(p Product) ProductResponse {
 		cid = &s
 	}
 
+	var snapshotID *string
+	if p.MeasurementSnapshotID != nil {
+		s := p.MeasurementSnapshotID.String()
+		snapshotID = &s
+	}
+
 	var datePaid *string
 	if p.DatePaid != nil {
 		s := p.DatePaid.Format("2006-01-02 15:04:05")
 		datePaid = &s
 	}
 
+	var taxProfileID *string
+	if p.TaxProfileID != nil {
+		s := p.TaxProfileID.String()
+		taxProfileID = &s
+	}
+
+	var acceptedQuoteID, quoteAcceptedAt *string
+	if p.AcceptedQuoteID != nil {
+		s := p.AcceptedQuoteID.String()
+		acceptedQuoteID = &s
+	}
+	if p.QuoteAcceptedAt != nil {
+		s := p.QuoteAcceptedAt.Format("2006-01-02 15:04:05")
+		quoteAcceptedAt = &s
+	}
+
 	var images []ProductImageResponse
 	for _, img := range p.Images {
 		images = append(images, ProductImageResponse{
@@ -304,24 +640,44 @@ func mapToResponse(p Product) ProductResponse {
 	}
 
 	return ProductResponse{
-		ID:                   p.ID.String(),
-		UserID:               fmt.Sprintf("%d", p.UserID),
-		Name:                 p.Name,
-		ClientID:             cid,
-		MaterialsCost:        p.MaterialsCost,
-		HoursCost:            p.HoursCost,
-		ProfitPercentage:     p.ProfitPercentage,
-		IncludeFixedExpenses: p.IncludeFixedExpenses,
-		FixedExpenseRate:     p.FixedExpenseRate,
-		Subtotal:             p.Subtotal,
-		FixedExpensesAmount:  p.FixedExpensesAmount,
-		BaseTotal:            p.BaseTotal,
-		ProfitAmount:         p.ProfitAmount,
-		Total:                p.Total,
-		Status:               p.Status,
-		Images:               images,
-		DatePaid:             datePaid,
-		CreatedAt:            p.CreatedAt.Format("2006-01-02 15:04:05"),
-		UpdatedAt:            p.UpdatedAt.Format("2006-01-02 15:04:05"),
+		ID:                    p.ID.String(),
+		UserID:                fmt.Sprintf("%d", p.UserID),
+		Name:                  p.Name,
+		ClientID:              cid,
+		MeasurementSnapshotID: snapshotID,
+		MaterialsCost:         p.MaterialsCost,
+		HoursCost:             p.HoursCost,
+		EstimatedHours:        p.EstimatedHours,
+		LoggedHours:           p.LoggedHours,
+		ProfitPercentage:      p.ProfitPercentage,
+		IncludeFixedExpenses:  p.IncludeFixedExpenses,
+		FixedExpenseRate:      p.FixedExpenseRate,
+		Subtotal:              p.Subtotal,
+		FixedExpensesAmount:   p.FixedExpensesAmount,
+		BaseTotal:             p.BaseTotal,
+		ProfitAmount:          p.ProfitAmount,
+		Adjustments:           mapAdjustmentsToResponse(p),
+		DiscountAmount:        p.DiscountAmount,
+		SurchargeAmount:       p.SurchargeAmount,
+		TaxProfileID:          taxProfileID,
+		TaxRate:               p.TaxRate,
+		TaxInclusive:          p.TaxInclusive,
+		TaxAmount:             p.TaxAmount,
+		Total:                 p.Total,
+		Currency:              p.Currency,
+		Status:                p.Status,
+		AmountPaid:            amountPaid(p),
+		OutstandingBalance:    outstandingBalance(p),
+		Images:                images,
+		Materials:             mapMaterialsToResponse(p.Materials),
+		StockConsumed:         p.StockConsumed,
+		StockWarnings:         stockWarnings(p),
+		DatePaid:              datePaid,
+		DueDate:               formatDate(p.DueDate),
+		Fittings:              mapFittingsToResponse(p.Fittings),
+		AcceptedQuoteID:       acceptedQuoteID,
+		QuoteAcceptedAt:       quoteAcceptedAt,
+		CreatedAt:             p.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:             p.UpdatedAt.Format("2006-01-02 15:04:05"),
 	}
 }
@@ -0,0 +1,189 @@
+package products
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/TFX0019/api-go-gds/pkg/config"
+	"github.com/TFX0019/api-go-gds/pkg/utils"
+)
+
+var (
+	ErrProductNotFound   = errors.New("product not found")
+	ErrShareLinkNotFound = errors.New("share link not found")
+)
+
+func (s *service) CreateShareLink(userID, productID string, req CreateShareLinkRequest) (*ShareLinkResponse, error) {
+	product, err := s.findOwned(userID, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := generateShareToken()
+	if err != nil {
+		return nil, err
+	}
+
+	link := &ProductShareLink{
+		ProductID: product.ID,
+		UserID:    product.UserID,
+		Token:     token,
+	}
+	if req.ExpiresInDays > 0 {
+		expiresAt := time.Now().AddDate(0, 0, req.ExpiresInDays)
+		link.ExpiresAt = &expiresAt
+	}
+
+	if err := s.repo.CreateShareLink(link); err != nil {
+		return nil, err
+	}
+
+	res := mapShareLinkToResponse(*link)
+	return &res, nil
+}
+
+func (s *service) GetShareLinks(userID, productID string) ([]ShareLinkResponse, error) {
+	if _, err := s.findOwned(userID, productID); err != nil {
+		return nil, err
+	}
+
+	links, err := s.repo.FindShareLinks(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]ShareLinkResponse, 0, len(links))
+	for _, l := range links {
+		res = append(res, mapShareLinkToResponse(l))
+	}
+	return res, nil
+}
+
+func (s *service) RevokeShareLink(userID, productID, linkID string) error {
+	if _, err := s.findOwned(userID, productID); err != nil {
+		return err
+	}
+
+	link, err := s.repo.FindShareLinkByID(linkID)
+	if err != nil || link.ProductID.String() != productID {
+		return ErrShareLinkNotFound
+	}
+	if link.RevokedAt != nil {
+		return nil
+	}
+
+	now := time.Now()
+	link.RevokedAt = &now
+	return s.repo.UpdateShareLink(link)
+}
+
+// GetPublicOrder returns the order behind a share link. Unknown, expired and
+// revoked links all look the same to the caller.
+func (s *service) GetPublicOrder(token string) (*PublicOrderResponse, error) {
+	link, err := s.repo.FindShareLinkByToken(token)
+	if err != nil || !link.Active() {
+		return nil, ErrShareLinkNotFound
+	}
+
+	product, err := s.repo.FindByID(link.ProductID.String())
+	if err != nil {
+		return nil, ErrShareLinkNotFound
+	}
+
+	res := &PublicOrderResponse{
+		Name:               product.Name,
+		Status:             product.Status,
+		DueDate:            formatDate(product.DueDate),
+		Images:             []string{},
+		Total:              product.Total,
+		OutstandingBalance: outstandingBalance(*product),
+		Currency:           product.Currency,
+	}
+	res.Workshop, res.Locale = s.workshop(product.UserID)
+	for _, img := range product.Images {
+		res.Images = append(res.Images, publicURL(img.Path))
+	}
+
+	return res, nil
+}
+
+// workshop is how the member is presented on public pages, along with the
+// locale amounts are written in.
+func (s *service) workshop(userID uint) (WorkshopResponse, string) {
+	var res WorkshopResponse
+	locale := utils.DefaultLocale
+	if user, err := s.authRepo.FindByID(userID); err == nil {
+		res.Name = user.Name
+		if user.BusinessName != "" {
+			res.Name = user.BusinessName
+		}
+		if user.Avatar != nil && *user.Avatar != "" {
+			res.AvatarURL = publicURL(*user.Avatar)
+		}
+		if user.Locale != "" {
+			locale = user.Locale
+		}
+	}
+	return res, locale
+}
+
+func (s *service) findOwned(userID, productID string) (*Product, error) {
+	product, err := s.repo.FindByID(productID)
+	if err != nil || fmt.Sprintf("%d", product.UserID) != userID {
+		return nil, ErrProductNotFound
+	}
+	return product, nil
+}
+
+// outstandingBalance is what the customer still owes for the order after
+// the payments received so far.
+func outstandingBalance(p Product) utils.Money {
+	if p.Status == StatusCancelled {
+		return utils.Zero
+	}
+	return utils.MaxMoney(p.Total.Sub(amountPaid(p)), utils.Zero)
+}
+
+func generateShareToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// publicURL turns a stored upload path into an absolute URL.
+func publicURL(path string) string {
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		return path
+	}
+	baseURL := config.GetEnv("API_URL", "http://localhost:3000")
+	return strings.TrimRight(baseURL, "/") + "/" + strings.TrimLeft(path, "/")
+}
+
+func mapShareLinkToResponse(l ProductShareLink) ShareLinkResponse {
+	var expiresAt, revokedAt *string
+	if l.ExpiresAt != nil {
+		s := l.ExpiresAt.Format("2006-01-02 15:04:05")
+		expiresAt = &s
+	}
+	if l.RevokedAt != nil {
+		s := l.RevokedAt.Format("2006-01-02 15:04:05")
+		revokedAt = &s
+	}
+
+	return ShareLinkResponse{
+		ID:        l.ID.String(),
+		ProductID: l.ProductID.String(),
+		Token:     l.Token,
+		URL:       publicURL("orders/" + l.Token),
+		Active:    l.Active(),
+		ExpiresAt: expiresAt,
+		RevokedAt: revokedAt,
+		CreatedAt: l.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
@@ -0,0 +1,72 @@
+package products
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	StatusPending   = "pending"
+	StatusInProcess = "in_process"
+	StatusComplete  = "complete"
+	StatusDelivered = "delivered"
+	StatusPaid      = "paid"
+	StatusCancelled = "cancelled"
+)
+
+var ErrInvalidTransition = errors.New("invalid status transition")
+
+// statusTransitions lists the statuses each status can move to. Orders can
+// be paid before or after being delivered, and cancelled ones reopened.
+var statusTransitions = map[string][]string{
+	StatusPending:   {StatusInProcess, StatusCancelled},
+	StatusInProcess: {StatusPending, StatusComplete, StatusCancelled},
+	StatusComplete:  {StatusInProcess, StatusDelivered, StatusPaid, StatusCancelled},
+	StatusDelivered: {StatusComplete, StatusPaid},
+	StatusPaid:      {StatusDelivered},
+	StatusCancelled: {StatusPending},
+}
+
+// CanTransition reports whether an order can move from one status to another.
+func CanTransition(from, to string) bool {
+	for _, status := range statusTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+func checkTransition(from, to string) error {
+	if from == to {
+		return fmt.Errorf("%w: the product is already %s", ErrInvalidTransition, to)
+	}
+	if !CanTransition(from, to) {
+		return fmt.Errorf("%w: cannot go from %s to %s", ErrInvalidTransition, from, to)
+	}
+	return nil
+}
+
+func (s *service) GetStatusHistory(userID, productID string) ([]StatusHistoryResponse, error) {
+	if _, err := s.findOwned(userID, productID); err != nil {
+		return nil, err
+	}
+
+	history, err := s.repo.FindStatusHistory(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]StatusHistoryResponse, 0, len(history))
+	for _, h := range history {
+		res = append(res, StatusHistoryResponse{
+			ID:         h.ID.String(),
+			FromStatus: h.FromStatus,
+			ToStatus:   h.ToStatus,
+			ChangedBy:  h.ChangedBy,
+			Note:       h.Note,
+			CreatedAt:  h.CreatedAt.Format("2006-01-02 15:04:05"),
+		})
+	}
+	return res, nil
+}
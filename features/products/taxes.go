@@ -0,0 +1,114 @@
+package products
+
+import (
+	"errors"
+	"time"
+
+	"github.com/TFX0019/api-go-gds/features/tax_profiles"
+	"github.com/TFX0019/api-go-gds/pkg/utils"
+)
+
+// resolveTaxProfile returns the user's profile with the given id, or nil for
+// an empty id, which leaves the order untaxed.
+func (s *service) resolveTaxProfile(userID uint, id string) (*tax_profiles.TaxProfile, error) {
+	if id == "" {
+		return nil, nil
+	}
+	profile, err := s.taxProfilesRepo.FindByID(id)
+	if err != nil || profile.UserID != userID {
+		return nil, errors.New("tax profile not found")
+	}
+	return profile, nil
+}
+
+// setTax copies the profile's rate to the order, or removes the tax for nil.
+func (p *Product) setTax(profile *tax_profiles.TaxProfile) {
+	if profile == nil {
+		p.TaxProfileID = nil
+		p.TaxRate = 0
+		p.TaxInclusive = false
+		return
+	}
+	p.TaxProfileID = &profile.ID
+	p.TaxRate = profile.Rate
+	p.TaxInclusive = profile.Inclusive
+}
+
+// netTotal is the order's price before tax.
+func (p Product) netTotal() utils.Money {
+	return p.Total.Sub(p.TaxAmount)
+}
+
+// GetTaxReport reports the tax collected each month between query.From and
+// query.To, converting orders priced in other currencies with the current
+// exchange rates.
+func (s *service) GetTaxReport(userID string, query TaxReportQuery) (*TaxReportResponse, error) {
+	currency, err := s.reportCurrency(userID, query.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	// Dates are UTC days, like the profit and loss report
+	to := today()
+	from := time.Date(to.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	if query.From != "" {
+		date, err := parseDate(&query.From)
+		if err != nil {
+			return nil, err
+		}
+		from = *date
+	}
+	if query.To != "" {
+		date, err := parseDate(&query.To)
+		if err != nil {
+			return nil, err
+		}
+		to = *date
+	}
+	if to.Before(from) {
+		return nil, errors.New("from must not be after to")
+	}
+
+	rows, err := s.repo.GetTaxReport(userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	convert := s.converter(currency)
+	res := &TaxReportResponse{
+		Currency: currency,
+		From:     from.Format("2006-01-02"),
+		To:       to.Format("2006-01-02"),
+		Periods:  []TaxReportPeriodResponse{},
+	}
+	// Rows come sorted by period, one per currency
+	for _, row := range rows {
+		received, err := convert(row.TotalReceived, row.Currency)
+		if err != nil {
+			return nil, err
+		}
+		tax, err := convert(row.TaxCollected, row.Currency)
+		if err != nil {
+			return nil, err
+		}
+
+		if n := len(res.Periods); n == 0 || res.Periods[n-1].Period != row.Period {
+			res.Periods = append(res.Periods, TaxReportPeriodResponse{Period: row.Period})
+		}
+		period := &res.Periods[len(res.Periods)-1]
+		period.TotalReceived = period.TotalReceived.Add(received)
+		period.TaxCollected = period.TaxCollected.Add(tax)
+	}
+
+	for i := range res.Periods {
+		period := &res.Periods[i]
+		period.TotalReceived = period.TotalReceived.Round()
+		period.TaxCollected = period.TaxCollected.Round()
+		period.NetAmount = period.TotalReceived.Sub(period.TaxCollected)
+
+		res.TotalReceived = res.TotalReceived.Add(period.TotalReceived)
+		res.TaxCollected = res.TaxCollected.Add(period.TaxCollected)
+	}
+	res.NetAmount = res.TotalReceived.Sub(res.TaxCollected)
+	return res, nil
+}
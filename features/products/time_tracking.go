@@ -0,0 +1,272 @@
+package products
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/TFX0019/api-go-gds/pkg/utils"
+	"github.com/google/uuid"
+)
+
+var ErrTimeEntryNotFound = errors.New("time entry not found")
+
+func (s *service) GetTimeTracking(userID, productID string) (*TimeTrackingResponse, error) {
+	product, err := s.findOwned(userID, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.repo.FindTimeEntries(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &TimeTrackingResponse{
+		EstimatedHours: product.EstimatedHours,
+		Entries:        make([]TimeEntryResponse, 0, len(entries)),
+	}
+
+	var logged float64
+	var cost utils.Money
+	for _, e := range entries {
+		entry := mapTimeEntryToResponse(e)
+		if entry.Running {
+			running := entry
+			res.Running = &running
+		}
+		logged += e.Hours()
+		cost = cost.Add(e.cost())
+		res.Entries = append(res.Entries, entry)
+	}
+
+	// Includes the time of a running timer, which HoursCost only counts once stopped
+	res.LoggedHours = roundHours(logged)
+	res.DifferenceHours = roundHours(logged - product.EstimatedHours)
+	res.HoursCost = cost.Round()
+
+	return res, nil
+}
+
+func (s *service) StartTimer(userID, productID string, req StartTimerRequest) (*TimeEntryResponse, error) {
+	product, err := s.findOwned(userID, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.FindRunningTimeEntry(productID, product.UserID); err == nil {
+		return nil, errors.New("a timer is already running for this product")
+	}
+
+	taskID, err := s.resolveTask(product, req.TaskID)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.authRepo.FindByID(product.UserID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	entry := &TimeEntry{
+		ProductID:  product.ID,
+		UserID:     product.UserID,
+		TaskID:     taskID,
+		StartedAt:  time.Now(),
+		HourlyRate: user.HourlyRate,
+		Note:       req.Note,
+	}
+	if err := s.repo.CreateTimeEntry(entry); err != nil {
+		return nil, err
+	}
+
+	res := mapTimeEntryToResponse(*entry)
+	return &res, nil
+}
+
+func (s *service) StopTimer(userID, productID string) (*TimeEntryResponse, error) {
+	product, err := s.findOwned(userID, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := s.repo.FindRunningTimeEntry(productID, product.UserID)
+	if err != nil {
+		return nil, errors.New("no timer is running for this product")
+	}
+
+	now := time.Now()
+	entry.EndedAt = &now
+	entry.DurationSeconds = int64(now.Sub(entry.StartedAt).Seconds())
+	if err := s.repo.UpdateTimeEntry(entry); err != nil {
+		return nil, err
+	}
+
+	if err := s.recalculateHoursCost(product); err != nil {
+		return nil, err
+	}
+
+	res := mapTimeEntryToResponse(*entry)
+	return &res, nil
+}
+
+func (s *service) AddTimeEntry(userID, productID string, req ManualTimeEntryRequest) (*TimeEntryResponse, error) {
+	product, err := s.findOwned(userID, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	taskID, err := s.resolveTask(product, req.TaskID)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.authRepo.FindByID(product.UserID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	duration := time.Duration(req.Minutes * float64(time.Minute))
+	endedAt := time.Now()
+	startedAt := endedAt.Add(-duration)
+	if req.StartedAt != nil {
+		startedAt, err = time.Parse(time.RFC3339, *req.StartedAt)
+		if err != nil {
+			return nil, errors.New("started_at must be an RFC 3339 date")
+		}
+		endedAt = startedAt.Add(duration)
+	}
+
+	entry := &TimeEntry{
+		ProductID:       product.ID,
+		UserID:          product.UserID,
+		TaskID:          taskID,
+		StartedAt:       startedAt,
+		EndedAt:         &endedAt,
+		DurationSeconds: int64(duration.Seconds()),
+		HourlyRate:      user.HourlyRate,
+		Note:            req.Note,
+	}
+	if err := s.repo.CreateTimeEntry(entry); err != nil {
+		return nil, err
+	}
+
+	if err := s.recalculateHoursCost(product); err != nil {
+		return nil, err
+	}
+
+	res := mapTimeEntryToResponse(*entry)
+	return &res, nil
+}
+
+func (s *service) DeleteTimeEntry(userID, productID, entryID string) error {
+	product, err := s.findOwned(userID, productID)
+	if err != nil {
+		return err
+	}
+
+	entry, err := s.repo.FindTimeEntryByID(entryID)
+	if err != nil || entry.ProductID != product.ID {
+		return ErrTimeEntryNotFound
+	}
+
+	if err := s.repo.DeleteTimeEntry(entryID); err != nil {
+		return err
+	}
+
+	return s.recalculateHoursCost(product)
+}
+
+// resolveTask checks the task belongs to the user and, when it is linked to
+// a product, that it is this one.
+func (s *service) resolveTask(product *Product, taskID *string) (*uuid.UUID, error) {
+	if taskID == nil || *taskID == "" {
+		return nil, nil
+	}
+
+	id, err := uuid.Parse(*taskID)
+	if err != nil {
+		return nil, errors.New("invalid task id")
+	}
+
+	linkedProduct, err := s.repo.FindTaskProductID(*taskID, product.UserID)
+	if err != nil {
+		return nil, errors.New("task not found")
+	}
+	if linkedProduct != nil && *linkedProduct != product.ID {
+		return nil, errors.New("task belongs to another product")
+	}
+	return &id, nil
+}
+
+// recalculateHoursCost derives HoursCost from the finished time entries, or
+// goes back to the hand-entered one when there are none, and reprices the
+// product. The price of an accepted quote is kept, so only the
+// logged hours are updated then.
+func (s *service) recalculateHoursCost(product *Product) error {
+	entries, err := s.repo.FindTimeEntries(product.ID.String())
+	if err != nil {
+		return err
+	}
+
+	var logged float64
+	var cost utils.Money
+	for _, e := range entries {
+		if e.EndedAt == nil {
+			continue
+		}
+		logged += e.Hours()
+		cost = cost.Add(e.cost())
+	}
+
+	product.LoggedHours = roundHours(logged)
+	if logged == 0 {
+		cost = product.ManualHoursCost
+	}
+	if product.AcceptedQuoteID != nil {
+		return s.repo.Update(product)
+	}
+
+	price := CalculatePrice(PricingRequest{
+		MaterialsCost:        product.MaterialsCost,
+		HoursCost:            cost,
+		ProfitPercentage:     product.ProfitPercentage,
+		IncludeFixedExpenses: product.IncludeFixedExpenses,
+		FixedExpenseRate:     product.FixedExpenseRate,
+		Adjustments:          adjustmentRequests(product.Adjustments),
+		TaxRate:              product.TaxRate,
+		TaxInclusive:         product.TaxInclusive,
+	})
+	price.apply(product)
+
+	return s.repo.Update(product)
+}
+
+func roundHours(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+func mapTimeEntryToResponse(e TimeEntry) TimeEntryResponse {
+	var taskID, endedAt *string
+	if e.TaskID != nil {
+		s := e.TaskID.String()
+		taskID = &s
+	}
+	if e.EndedAt != nil {
+		s := e.EndedAt.Format("2006-01-02 15:04:05")
+		endedAt = &s
+	}
+
+	return TimeEntryResponse{
+		ID:         e.ID.String(),
+		ProductID:  e.ProductID.String(),
+		TaskID:     taskID,
+		StartedAt:  e.StartedAt.Format("2006-01-02 15:04:05"),
+		EndedAt:    endedAt,
+		Running:    e.EndedAt == nil,
+		Hours:      roundHours(e.Hours()),
+		HourlyRate: e.HourlyRate,
+		Cost:       e.cost().Round(),
+		Note:       e.Note,
+	}
+}
@@ -0,0 +1,186 @@
+package size_charts
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/TFX0019/api-go-gds/features/customers"
+	"github.com/TFX0019/api-go-gds/pkg/utils"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type Controller struct {
+	service  Service
+	validate *validator.Validate
+}
+
+func NewController(service Service) *Controller {
+	return &Controller{
+		service:  service,
+		validate: validator.New(),
+	}
+}
+
+func (c *Controller) Create(ctx *fiber.Ctx) error {
+	var req CreateSizeChartRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "invalid request body")
+	}
+
+	if err := c.validate.Struct(req); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, utils.ParseValidationError(err))
+	}
+
+	res, err := c.service.Create(req)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, err.Error())
+	}
+
+	return utils.SendCreated(ctx, res, "size chart created successfully")
+}
+
+func (c *Controller) GetAll(ctx *fiber.Ctx) error {
+	res, err := c.service.GetAll()
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusInternalServerError, err.Error())
+	}
+	return utils.SendSuccess(ctx, res, "size charts retrieved successfully")
+}
+
+func (c *Controller) GetAllAdmin(ctx *fiber.Ctx) error {
+	res, err := c.service.GetAllAdmin()
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusInternalServerError, err.Error())
+	}
+	return utils.SendSuccess(ctx, res, "size charts retrieved successfully")
+}
+
+func (c *Controller) GetByID(ctx *fiber.Ctx) error {
+	id := ctx.Params("id")
+	if id == "" {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "id required")
+	}
+
+	res, err := c.service.GetByID(id)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusNotFound, err.Error())
+	}
+
+	return utils.SendSuccess(ctx, res, "size chart retrieved successfully")
+}
+
+func (c *Controller) Update(ctx *fiber.Ctx) error {
+	id := ctx.Params("id")
+	if id == "" {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "id required")
+	}
+
+	var req UpdateSizeChartRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "invalid request body")
+	}
+
+	if err := c.validate.Struct(req); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, utils.ParseValidationError(err))
+	}
+
+	res, err := c.service.Update(id, req)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(ctx, res, "size chart updated successfully")
+}
+
+func (c *Controller) Activate(ctx *fiber.Ctx) error {
+	id := ctx.Params("id")
+	if id == "" {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "id required")
+	}
+
+	if err := c.service.Activate(id); err != nil {
+		return utils.SendError(ctx, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(ctx, nil, "size chart activated successfully")
+}
+
+func (c *Controller) Deactivate(ctx *fiber.Ctx) error {
+	id := ctx.Params("id")
+	if id == "" {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "id required")
+	}
+
+	if err := c.service.Deactivate(id); err != nil {
+		return utils.SendError(ctx, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(ctx, nil, "size chart deactivated successfully")
+}
+
+func (c *Controller) Delete(ctx *fiber.Ctx) error {
+	id := ctx.Params("id")
+	if id == "" {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "id required")
+	}
+
+	if err := c.service.Delete(id); err != nil {
+		return utils.SendError(ctx, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(ctx, nil, "size chart deleted successfully")
+}
+
+func (c *Controller) Suggest(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	var req SuggestSizeRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "invalid request body")
+	}
+
+	if err := c.validate.Struct(req); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, utils.ParseValidationError(err))
+	}
+
+	res, err := c.service.Suggest(userID, req)
+	if err != nil {
+		if errors.Is(err, customers.ErrCustomerNotFound) {
+			return utils.SendError(ctx, fiber.StatusNotFound, err.Error())
+		}
+		return utils.SendError(ctx, fiber.StatusBadRequest, err.Error())
+	}
+
+	return utils.SendSuccess(ctx, res, "size suggestions retrieved successfully")
+}
+
+func getUserIDFromToken(ctx *fiber.Ctx) (string, error) {
+	userToken := ctx.Locals("user")
+	if userToken == nil {
+		return "", fmt.Errorf("no user in context")
+	}
+
+	token, ok := userToken.(*jwt.Token)
+	if !ok {
+		return "", fmt.Errorf("invalid token type")
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return "", fmt.Errorf("invalid claims")
+	}
+
+	switch v := claims["user_id"].(type) {
+	case string:
+		return v, nil
+	case float64:
+		return fmt.Sprintf("%.0f", v), nil
+	default:
+		return "", fmt.Errorf("invalid user_id type in token")
+	}
+}
@@ -0,0 +1,73 @@
+package size_charts
+
+type SizeRequest struct {
+	Label    string                      `json:"label" validate:"required"`
+	Position int                         `json:"position" validate:"gte=0"`
+	Ranges   map[string]MeasurementRange `json:"ranges" validate:"required,min=1"`
+}
+
+type CreateSizeChartRequest struct {
+	Name        string        `json:"name" validate:"required"`
+	System      string        `json:"system"`
+	Category    string        `json:"category"`
+	Description string        `json:"description"`
+	Sizes       []SizeRequest `json:"sizes" validate:"required,min=1,dive"`
+}
+
+type UpdateSizeChartRequest struct {
+	Name        string        `json:"name"`
+	System      string        `json:"system"`
+	Category    string        `json:"category"`
+	Description string        `json:"description"`
+	Sizes       []SizeRequest `json:"sizes" validate:"omitempty,dive"`
+}
+
+type SuggestSizeRequest struct {
+	CustomerID   string             `json:"customer_id" validate:"omitempty,uuid"`
+	Measurements map[string]float64 `json:"measurements"`
+	// Unit of the measurements sent in the request, cm by default
+	Unit    string `json:"unit" validate:"omitempty,oneof=cm in"`
+	ChartID string `json:"chart_id" validate:"omitempty,uuid"`
+	// Apply stores the suggested size on the customer; requires customer_id and chart_id
+	Apply bool `json:"apply"`
+}
+
+type SizeResponse struct {
+	ID       string                      `json:"id"`
+	Label    string                      `json:"label"`
+	Position int                         `json:"position"`
+	Ranges   map[string]MeasurementRange `json:"ranges"`
+}
+
+type SizeChartResponse struct {
+	ID          string         `json:"id"`
+	Name        string         `json:"name"`
+	System      string         `json:"system"`
+	Category    string         `json:"category"`
+	Description string         `json:"description"`
+	Active      bool           `json:"active"`
+	Unit        string         `json:"unit"`
+	Sizes       []SizeResponse `json:"sizes"`
+	CreatedAt   string         `json:"created_at"`
+	UpdatedAt   string         `json:"updated_at"`
+}
+
+type MeasurementFit struct {
+	Key    string  `json:"key"`
+	Value  float64 `json:"value"`
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+	Within bool    `json:"within"`
+}
+
+type SizeSuggestion struct {
+	ChartID   string           `json:"chart_id"`
+	ChartName string           `json:"chart_name"`
+	System    string           `json:"system"`
+	Category  string           `json:"category"`
+	Size      string           `json:"size"`
+	FitScore  float64          `json:"fit_score"`
+	Unit      string           `json:"unit"`
+	Details   []MeasurementFit `json:"details"`
+	Applied   bool             `json:"applied"`
+}
@@ -0,0 +1,45 @@
+package size_charts
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MeasurementRange is the inclusive range, in centimeters, a measurement must
+// fall into for a size to fit.
+type MeasurementRange struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+}
+
+type SizeChart struct {
+	ID          uuid.UUID       `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	Name        string          `gorm:"type:text;not null"`
+	System      string          `gorm:"type:text"` // EU, US, UK...
+	Category    string          `gorm:"type:text"` // women, men, children...
+	Description string          `gorm:"type:text"`
+	Active      bool            `gorm:"default:true"`
+	Sizes       []SizeChartSize `gorm:"foreignKey:ChartID;constraint:OnDelete:CASCADE;"`
+	CreatedAt   time.Time       `gorm:"not null;default:now()"`
+	UpdatedAt   time.Time       `gorm:"not null;default:now()"`
+}
+
+func (SizeChart) TableName() string {
+	return "size_charts"
+}
+
+type SizeChartSize struct {
+	ID       uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	ChartID  uuid.UUID `gorm:"type:uuid;not null;index"`
+	Label    string    `gorm:"type:text;not null"`
+	Position int       `gorm:"not null;default:0"`
+	// Ranges are keyed by customer measurement name (bust_chest, waist, hip...)
+	Ranges    map[string]MeasurementRange `gorm:"serializer:json"`
+	CreatedAt time.Time                   `gorm:"not null;default:now()"`
+	UpdatedAt time.Time                   `gorm:"not null;default:now()"`
+}
+
+func (SizeChartSize) TableName() string {
+	return "size_chart_sizes"
+}
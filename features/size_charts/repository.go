@@ -0,0 +1,77 @@
+package size_charts
+
+import (
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	Create(chart *SizeChart) error
+	FindAll(activeOnly bool) ([]SizeChart, error)
+	FindByID(id string) (*SizeChart, error)
+	Update(chart *SizeChart, sizes []SizeChartSize) error
+	Delete(id string) error
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) Create(chart *SizeChart) error {
+	return r.db.Create(chart).Error
+}
+
+func (r *repository) FindAll(activeOnly bool) ([]SizeChart, error) {
+	var charts []SizeChart
+	query := r.db.Preload("Sizes", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position asc")
+	})
+	if activeOnly {
+		query = query.Where("active = ?", true)
+	}
+	if err := query.Order("name asc").Find(&charts).Error; err != nil {
+		return nil, err
+	}
+	return charts, nil
+}
+
+func (r *repository) FindByID(id string) (*SizeChart, error) {
+	var chart SizeChart
+	err := r.db.Preload("Sizes", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position asc")
+	}).First(&chart, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &chart, nil
+}
+
+// Update saves the chart and, when sizes is not nil, replaces all its sizes.
+func (r *repository) Update(chart *SizeChart, sizes []SizeChartSize) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Sizes").Save(chart).Error; err != nil {
+			return err
+		}
+		if sizes == nil {
+			return nil
+		}
+		if err := tx.Where("chart_id = ?", chart.ID).Delete(&SizeChartSize{}).Error; err != nil {
+			return err
+		}
+		for i := range sizes {
+			sizes[i].ChartID = chart.ID
+		}
+		if err := tx.Create(&sizes).Error; err != nil {
+			return err
+		}
+		chart.Sizes = sizes
+		return nil
+	})
+}
+
+func (r *repository) Delete(id string) error {
+	return r.db.Delete(&SizeChart{}, "id = ?", id).Error
+}
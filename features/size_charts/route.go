@@ -0,0 +1,24 @@
+package size_charts
+
+import (
+	"github.com/TFX0019/api-go-gds/pkg/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+func RegisterRoutes(app fiber.Router, controller *Controller) {
+	route := app.Group("/api/size-charts", middleware.Protected())
+
+	// Admin only routes
+	adminRoute := route.Group("/admin", middleware.RequireRole("admin"))
+	adminRoute.Get("/", controller.GetAllAdmin)
+	adminRoute.Post("/", controller.Create)
+	adminRoute.Put("/:id", controller.Update)
+	adminRoute.Patch("/:id/activate", controller.Activate)
+	adminRoute.Patch("/:id/deactivate", controller.Deactivate)
+	adminRoute.Delete("/:id", controller.Delete)
+
+	// Accessible to all roles
+	route.Get("/", controller.GetAll)
+	route.Post("/suggest", controller.Suggest)
+	route.Get("/:id", controller.GetByID)
+}
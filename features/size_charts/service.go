@@ -0,0 +1,329 @@
+package size_charts
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/TFX0019/api-go-gds/features/customers"
+	"github.com/TFX0019/api-go-gds/pkg/utils"
+)
+
+type Service interface {
+	Create(req CreateSizeChartRequest) (*SizeChartResponse, error)
+	GetAll() ([]SizeChartResponse, error)
+	GetAllAdmin() ([]SizeChartResponse, error)
+	GetByID(id string) (*SizeChartResponse, error)
+	Update(id string, req UpdateSizeChartRequest) (*SizeChartResponse, error)
+	Activate(id string) error
+	Deactivate(id string) error
+	Delete(id string) error
+	Suggest(userID string, req SuggestSizeRequest) ([]SizeSuggestion, error)
+}
+
+type service struct {
+	repo          Repository
+	customersRepo customers.Repository
+}
+
+func NewService(repo Repository, customersRepo customers.Repository) Service {
+	return &service{repo: repo, customersRepo: customersRepo}
+}
+
+func (s *service) Create(req CreateSizeChartRequest) (*SizeChartResponse, error) {
+	sizes, err := buildSizes(req.Sizes)
+	if err != nil {
+		return nil, err
+	}
+
+	chart := &SizeChart{
+		Name:        req.Name,
+		System:      req.System,
+		Category:    req.Category,
+		Description: req.Description,
+		Active:      true,
+		Sizes:       sizes,
+	}
+
+	if err := s.repo.Create(chart); err != nil {
+		return nil, err
+	}
+
+	res := mapToResponse(*chart)
+	return &res, nil
+}
+
+func (s *service) GetAll() ([]SizeChartResponse, error) {
+	return s.list(true)
+}
+
+func (s *service) GetAllAdmin() ([]SizeChartResponse, error) {
+	return s.list(false)
+}
+
+func (s *service) list(activeOnly bool) ([]SizeChartResponse, error) {
+	charts, err := s.repo.FindAll(activeOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []SizeChartResponse{}
+	for _, c := range charts {
+		res = append(res, mapToResponse(c))
+	}
+	return res, nil
+}
+
+func (s *service) GetByID(id string) (*SizeChartResponse, error) {
+	chart, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("size chart not found")
+	}
+	res := mapToResponse(*chart)
+	return &res, nil
+}
+
+func (s *service) Update(id string, req UpdateSizeChartRequest) (*SizeChartResponse, error) {
+	chart, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("size chart not found")
+	}
+
+	if req.Name != "" {
+		chart.Name = req.Name
+	}
+	if req.System != "" {
+		chart.System = req.System
+	}
+	if req.Category != "" {
+		chart.Category = req.Category
+	}
+	if req.Description != "" {
+		chart.Description = req.Description
+	}
+
+	var sizes []SizeChartSize
+	if len(req.Sizes) > 0 {
+		sizes, err = buildSizes(req.Sizes)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.Update(chart, sizes); err != nil {
+		return nil, err
+	}
+
+	res := mapToResponse(*chart)
+	return &res, nil
+}
+
+func (s *service) Activate(id string) error {
+	return s.setActive(id, true)
+}
+
+func (s *service) Deactivate(id string) error {
+	return s.setActive(id, false)
+}
+
+func (s *service) setActive(id string, active bool) error {
+	chart, err := s.repo.FindByID(id)
+	if err != nil {
+		return errors.New("size chart not found")
+	}
+	chart.Active = active
+	return s.repo.Update(chart, nil)
+}
+
+func (s *service) Delete(id string) error {
+	_, err := s.repo.FindByID(id)
+	if err != nil {
+		return errors.New("size chart not found")
+	}
+	return s.repo.Delete(id)
+}
+
+func (s *service) Suggest(userID string, req SuggestSizeRequest) ([]SizeSuggestion, error) {
+	var customer *customers.Customer
+	if req.CustomerID != "" {
+		c, err := s.customersRepo.FindByID(req.CustomerID)
+		if err != nil || fmt.Sprintf("%d", c.UserID) != userID {
+			return nil, customers.ErrCustomerNotFound
+		}
+		customer = c
+	}
+
+	if req.Apply && (customer == nil || req.ChartID == "") {
+		return nil, errors.New("customer_id and chart_id are required to apply a size")
+	}
+
+	// Explicit measurements override the customer's stored ones
+	measurements := map[string]float64{}
+	if customer != nil {
+		for key, value := range customer.Measurements.Fields() {
+			if *value != nil {
+				measurements[key] = **value
+			}
+		}
+	}
+	for key, value := range req.Measurements {
+		measurements[key] = utils.ToCentimeters(value, req.Unit)
+	}
+	if len(measurements) == 0 {
+		return nil, errors.New("no measurements to compare")
+	}
+
+	var charts []SizeChart
+	if req.ChartID != "" {
+		chart, err := s.repo.FindByID(req.ChartID)
+		if err != nil {
+			return nil, errors.New("size chart not found")
+		}
+		charts = []SizeChart{*chart}
+	} else {
+		all, err := s.repo.FindAll(true)
+		if err != nil {
+			return nil, err
+		}
+		charts = all
+	}
+
+	suggestions := []SizeSuggestion{}
+	for _, chart := range charts {
+		if suggestion, ok := suggestSize(chart, measurements); ok {
+			suggestions = append(suggestions, suggestion)
+		}
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].FitScore > suggestions[j].FitScore
+	})
+
+	if req.Apply {
+		if len(suggestions) == 0 {
+			return nil, errors.New("the customer's measurements cannot be compared with this chart")
+		}
+		customer.UsesStandardSize = true
+		customer.StandardSize = suggestions[0].Size
+		if err := s.customersRepo.Update(customer); err != nil {
+			return nil, err
+		}
+		suggestions[0].Applied = true
+	}
+
+	return suggestions, nil
+}
+
+// suggestSize picks the size of the chart that best fits the measurements.
+// Each comparable measurement scores 1 inside its range and loses score
+// linearly with the distance to it, relative to the range width; the fit
+// score is the average as a percentage.
+func suggestSize(chart SizeChart, measurements map[string]float64) (SizeSuggestion, bool) {
+	best := SizeSuggestion{FitScore: -1}
+
+	for _, size := range chart.Sizes {
+		var total float64
+		var details []MeasurementFit
+
+		keys := make([]string, 0, len(size.Ranges))
+		for key := range size.Ranges {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			value, ok := measurements[key]
+			if !ok {
+				continue
+			}
+			r := size.Ranges[key]
+
+			distance := 0.0
+			if value < r.Min {
+				distance = r.Min - value
+			} else if value > r.Max {
+				distance = value - r.Max
+			}
+
+			width := math.Max(r.Max-r.Min, 2)
+			total += math.Max(0, 1-distance/width)
+			details = append(details, MeasurementFit{
+				Key:    key,
+				Value:  value,
+				Min:    r.Min,
+				Max:    r.Max,
+				Within: distance == 0,
+			})
+		}
+
+		if len(details) == 0 {
+			continue
+		}
+
+		score := math.Round(total/float64(len(details))*1000) / 10
+		if score > best.FitScore {
+			best = SizeSuggestion{
+				ChartID:   chart.ID.String(),
+				ChartName: chart.Name,
+				System:    chart.System,
+				Category:  chart.Category,
+				Size:      size.Label,
+				FitScore:  score,
+				Unit:      utils.UnitCentimeters,
+				Details:   details,
+			}
+		}
+	}
+
+	return best, best.FitScore >= 0
+}
+
+func buildSizes(reqs []SizeRequest) ([]SizeChartSize, error) {
+	valid := map[string]bool{}
+	for _, key := range customers.MeasurementKeys {
+		valid[key] = true
+	}
+
+	var sizes []SizeChartSize
+	for _, r := range reqs {
+		for key, rng := range r.Ranges {
+			if !valid[key] {
+				return nil, fmt.Errorf("unknown measurement %s in size %s", key, r.Label)
+			}
+			if rng.Min < 0 || rng.Max < rng.Min {
+				return nil, fmt.Errorf("invalid range for %s in size %s", key, r.Label)
+			}
+		}
+		sizes = append(sizes, SizeChartSize{
+			Label:    r.Label,
+			Position: r.Position,
+			Ranges:   r.Ranges,
+		})
+	}
+	return sizes, nil
+}
+
+func mapToResponse(c SizeChart) SizeChartResponse {
+	sizes := []SizeResponse{}
+	for _, size := range c.Sizes {
+		sizes = append(sizes, SizeResponse{
+			ID:       size.ID.String(),
+			Label:    size.Label,
+			Position: size.Position,
+			Ranges:   size.Ranges,
+		})
+	}
+
+	return SizeChartResponse{
+		ID:          c.ID.String(),
+		Name:        c.Name,
+		System:      c.System,
+		Category:    c.Category,
+		Description: c.Description,
+		Active:      c.Active,
+		Unit:        utils.UnitCentimeters,
+		Sizes:       sizes,
+		CreatedAt:   c.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:   c.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
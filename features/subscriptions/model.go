@@ -2,6 +2,8 @@ package subscriptions
 
 import (
 	"time"
+
+	"github.com/TFX0019/api-go-gds/pkg/utils"
 )
 
 type SubscriptionStatus string
@@ -23,20 +25,20 @@ type Subscription struct {
 }
 
 type Transaction struct {
-	ID                    uint      `gorm:"primarykey" json:"id"`
-	UserID                uint      `gorm:"not null" json:"user_id"`
-	RevenueCatID          string    `gorm:"type:varchar(255);uniqueIndex;not null" json:"revenuecat_id"`
-	Type                  string    `gorm:"type:varchar(50);not null" json:"type"`
-	ProductID             string    `gorm:"type:varchar(255);not null" json:"product_id"`
-	Store                 string    `gorm:"type:varchar(50)" json:"store"`
-	Environment           string    `gorm:"type:varchar(50)" json:"environment"`
-	Currency              string    `gorm:"type:varchar(10)" json:"currency"`
-	Price                 float64   `json:"price"`
-	TransactionID         string    `gorm:"type:varchar(255)" json:"transaction_id"`
-	OriginalTransactionID string    `gorm:"type:varchar(255)" json:"original_transaction_id"`
-	EventTimestampMs      int64     `json:"event_timestamp_ms"`
-	PurchasedAtMs         int64     `json:"purchased_at_ms"`
-	ExpirationAtMs        int64     `json:"expiration_at_ms"`
-	CreatedAt             time.Time `json:"created_at"`
-	UpdatedAt             time.Time `json:"updated_at"`
+	ID                    uint        `gorm:"primarykey" json:"id"`
+	UserID                uint        `gorm:"not null" json:"user_id"`
+	RevenueCatID          string      `gorm:"type:varchar(255);uniqueIndex;not null" json:"revenuecat_id"`
+	Type                  string      `gorm:"type:varchar(50);not null" json:"type"`
+	ProductID             string      `gorm:"type:varchar(255);not null" json:"product_id"`
+	Store                 string      `gorm:"type:varchar(50)" json:"store"`
+	Environment           string      `gorm:"type:varchar(50)" json:"environment"`
+	Currency              string      `gorm:"type:varchar(10)" json:"currency"`
+	Price                 utils.Money `gorm:"type:numeric" json:"price"`
+	TransactionID         string      `gorm:"type:varchar(255)" json:"transaction_id"`
+	OriginalTransactionID string      `gorm:"type:varchar(255)" json:"original_transaction_id"`
+	EventTimestampMs      int64       `json:"event_timestamp_ms"`
+	PurchasedAtMs         int64       `json:"purchased_at_ms"`
+	ExpirationAtMs        int64       `json:"expiration_at_ms"`
+	CreatedAt             time.Time   `json:"created_at"`
+	UpdatedAt             time.Time   `json:"updated_at"`
 }
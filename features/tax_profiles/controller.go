@@ -0,0 +1,134 @@
+package tax_profiles
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/TFX0019/api-go-gds/pkg/utils"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type Controller struct {
+	service  Service
+	validate *validator.Validate
+}
+
+func NewController(service Service) *Controller {
+	return &Controller{
+		service:  service,
+		validate: validator.New(),
+	}
+}
+
+func (c *Controller) GetAll(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	res, err := c.service.GetAll(userID)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusInternalServerError, err.Error())
+	}
+	return utils.SendSuccess(ctx, res, "tax profiles retrieved successfully")
+}
+
+func (c *Controller) Create(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	var req CreateTaxProfileRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "invalid request body")
+	}
+
+	if err := c.validate.Struct(req); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, utils.ParseValidationError(err))
+	}
+
+	res, err := c.service.Create(userID, req)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusInternalServerError, err.Error())
+	}
+	return utils.SendCreated(ctx, res, "tax profile created successfully")
+}
+
+func (c *Controller) Update(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	id := ctx.Params("id")
+	if id == "" {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "id required")
+	}
+
+	var req UpdateTaxProfileRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "invalid request body")
+	}
+
+	if err := c.validate.Struct(req); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, utils.ParseValidationError(err))
+	}
+
+	res, err := c.service.Update(userID, id, req)
+	if err != nil {
+		if errors.Is(err, ErrTaxProfileNotFound) {
+			return utils.SendError(ctx, fiber.StatusNotFound, err.Error())
+		}
+		return utils.SendError(ctx, fiber.StatusInternalServerError, err.Error())
+	}
+	return utils.SendSuccess(ctx, res, "tax profile updated successfully")
+}
+
+func (c *Controller) Delete(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromToken(ctx)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	id := ctx.Params("id")
+	if id == "" {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "id required")
+	}
+
+	if err := c.service.Delete(userID, id); err != nil {
+		if errors.Is(err, ErrTaxProfileNotFound) {
+			return utils.SendError(ctx, fiber.StatusNotFound, err.Error())
+		}
+		return utils.SendError(ctx, fiber.StatusInternalServerError, err.Error())
+	}
+	return utils.SendSuccess(ctx, nil, "tax profile deleted successfully")
+}
+
+func getUserIDFromToken(ctx *fiber.Ctx) (string, error) {
+	userToken := ctx.Locals("user")
+	if userToken == nil {
+		return "", fmt.Errorf("no user in context")
+	}
+
+	token, ok := userToken.(*jwt.Token)
+	if !ok {
+		return "", fmt.Errorf("invalid token type")
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return "", fmt.Errorf("invalid claims")
+	}
+
+	switch v := claims["user_id"].(type) {
+	case string:
+		return v, nil
+	case float64:
+		return fmt.Sprintf("%.0f", v), nil
+	default:
+		return "", fmt.Errorf("invalid user_id type in token")
+	}
+}
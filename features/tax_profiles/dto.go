@@ -0,0 +1,28 @@
+package tax_profiles
+
+type CreateTaxProfileRequest struct {
+	Name      string  `json:"name" validate:"required,max=60"`
+	Rate      float64 `json:"rate" validate:"gte=0,lte=100"`
+	Inclusive bool    `json:"inclusive"`
+	TaxID     string  `json:"tax_id" validate:"max=40"`
+	IsDefault bool    `json:"is_default"`
+}
+
+type UpdateTaxProfileRequest struct {
+	Name      *string  `json:"name" validate:"omitempty,max=60"`
+	Rate      *float64 `json:"rate" validate:"omitempty,gte=0,lte=100"`
+	Inclusive *bool    `json:"inclusive"`
+	TaxID     *string  `json:"tax_id" validate:"omitempty,max=40"`
+	IsDefault *bool    `json:"is_default"`
+}
+
+type TaxProfileResponse struct {
+	ID        string  `json:"id"`
+	Name      string  `json:"name"`
+	Rate      float64 `json:"rate"`
+	Inclusive bool    `json:"inclusive"`
+	TaxID     string  `json:"tax_id"`
+	IsDefault bool    `json:"is_default"`
+	CreatedAt string  `json:"created_at"`
+	UpdatedAt string  `json:"updated_at"`
+}
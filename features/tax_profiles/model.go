@@ -0,0 +1,26 @@
+package tax_profiles
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TaxProfile is a sales tax or VAT a user charges, such as IVA at 16%. The
+// default profile is applied to new orders.
+type TaxProfile struct {
+	ID     uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	UserID uint      `gorm:"not null;index"`
+	Name   string    `gorm:"type:text;not null"`
+	Rate   float64   `gorm:"type:numeric;not null"` // Percentage
+	// Inclusive prices already contain the tax; exclusive ones get it added
+	Inclusive bool      `gorm:"not null;default:false"`
+	TaxID     string    `gorm:"type:text"` // Registration number printed on documents
+	IsDefault bool      `gorm:"not null;default:false"`
+	CreatedAt time.Time `gorm:"not null;default:now()"`
+	UpdatedAt time.Time `gorm:"not null;default:now()"`
+}
+
+func (TaxProfile) TableName() string {
+	return "tax_profiles"
+}
@@ -0,0 +1,79 @@
+package tax_profiles
+
+import (
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	Create(profile *TaxProfile) error
+	FindByUserID(userID uint) ([]TaxProfile, error)
+	FindByID(id string) (*TaxProfile, error)
+	FindDefault(userID uint) (*TaxProfile, error)
+	Update(profile *TaxProfile) error
+	Delete(id string) error
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// Create saves the profile; a default profile replaces the user's previous
+// default.
+func (r *repository) Create(profile *TaxProfile) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := clearDefault(tx, profile); err != nil {
+			return err
+		}
+		return tx.Create(profile).Error
+	})
+}
+
+func (r *repository) FindByUserID(userID uint) ([]TaxProfile, error) {
+	var profiles []TaxProfile
+	err := r.db.Where("user_id = ?", userID).Order("is_default desc, name asc").Find(&profiles).Error
+	return profiles, err
+}
+
+func (r *repository) FindByID(id string) (*TaxProfile, error) {
+	var profile TaxProfile
+	if err := r.db.First(&profile, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+func (r *repository) FindDefault(userID uint) (*TaxProfile, error) {
+	var profile TaxProfile
+	if err := r.db.Where("user_id = ? AND is_default", userID).First(&profile).Error; err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+func (r *repository) Update(profile *TaxProfile) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := clearDefault(tx, profile); err != nil {
+			return err
+		}
+		return tx.Save(profile).Error
+	})
+}
+
+func (r *repository) Delete(id string) error {
+	return r.db.Delete(&TaxProfile{}, "id = ?", id).Error
+}
+
+// clearDefault unmarks the user's other profiles when profile becomes the
+// default one.
+func clearDefault(tx *gorm.DB, profile *TaxProfile) error {
+	if !profile.IsDefault {
+		return nil
+	}
+	return tx.Model(&TaxProfile{}).
+		Where("user_id = ? AND id <> ? AND is_default", profile.UserID, profile.ID).
+		Update("is_default", false).Error
+}
@@ -0,0 +1,15 @@
+package tax_profiles
+
+import (
+	"github.com/TFX0019/api-go-gds/pkg/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+func RegisterRoutes(app fiber.Router, controller *Controller) {
+	route := app.Group("/api/tax-profiles", middleware.Protected())
+
+	route.Get("/", controller.GetAll)
+	route.Post("/", controller.Create)
+	route.Put("/:id", controller.Update)
+	route.Delete("/:id", controller.Delete)
+}
@@ -0,0 +1,128 @@
+package tax_profiles
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+var ErrTaxProfileNotFound = errors.New("tax profile not found")
+
+type Service interface {
+	GetAll(userID string) ([]TaxProfileResponse, error)
+	Create(userID string, req CreateTaxProfileRequest) (*TaxProfileResponse, error)
+	Update(userID, id string, req UpdateTaxProfileRequest) (*TaxProfileResponse, error)
+	Delete(userID, id string) error
+}
+
+type service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) Service {
+	return &service{repo: repo}
+}
+
+func (s *service) GetAll(userID string) ([]TaxProfileResponse, error) {
+	uid, err := strconv.ParseUint(userID, 10, 32)
+	if err != nil {
+		return nil, errors.New("invalid user id")
+	}
+
+	profiles, err := s.repo.FindByUserID(uint(uid))
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]TaxProfileResponse, 0, len(profiles))
+	for _, p := range profiles {
+		res = append(res, mapToResponse(p))
+	}
+	return res, nil
+}
+
+func (s *service) Create(userID string, req CreateTaxProfileRequest) (*TaxProfileResponse, error) {
+	uid, err := strconv.ParseUint(userID, 10, 32)
+	if err != nil {
+		return nil, errors.New("invalid user id")
+	}
+
+	profile := &TaxProfile{
+		ID:        uuid.New(),
+		UserID:    uint(uid),
+		Name:      strings.TrimSpace(req.Name),
+		Rate:      req.Rate,
+		Inclusive: req.Inclusive,
+		TaxID:     strings.TrimSpace(req.TaxID),
+		IsDefault: req.IsDefault,
+	}
+	if err := s.repo.Create(profile); err != nil {
+		return nil, err
+	}
+
+	res := mapToResponse(*profile)
+	return &res, nil
+}
+
+// Update changes the profile for orders priced from now on; existing orders
+// keep the rate they were priced with.
+func (s *service) Update(userID, id string, req UpdateTaxProfileRequest) (*TaxProfileResponse, error) {
+	profile, err := s.findOwned(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil && strings.TrimSpace(*req.Name) != "" {
+		profile.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Rate != nil {
+		profile.Rate = *req.Rate
+	}
+	if req.Inclusive != nil {
+		profile.Inclusive = *req.Inclusive
+	}
+	if req.TaxID != nil {
+		profile.TaxID = strings.TrimSpace(*req.TaxID)
+	}
+	if req.IsDefault != nil {
+		profile.IsDefault = *req.IsDefault
+	}
+
+	if err := s.repo.Update(profile); err != nil {
+		return nil, err
+	}
+
+	res := mapToResponse(*profile)
+	return &res, nil
+}
+
+func (s *service) Delete(userID, id string) error {
+	if _, err := s.findOwned(userID, id); err != nil {
+		return err
+	}
+	return s.repo.Delete(id)
+}
+
+func (s *service) findOwned(userID, id string) (*TaxProfile, error) {
+	profile, err := s.repo.FindByID(id)
+	if err != nil || fmt.Sprintf("%d", profile.UserID) != userID {
+		return nil, ErrTaxProfileNotFound
+	}
+	return profile, nil
+}
+
+func mapToResponse(p TaxProfile) TaxProfileResponse {
+	return TaxProfileResponse{
+		ID:        p.ID.String(),
+		Name:      p.Name,
+		Rate:      p.Rate,
+		Inclusive: p.Inclusive,
+		TaxID:     p.TaxID,
+		IsDefault: p.IsDefault,
+		CreatedAt: p.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt: p.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
@@ -0,0 +1,101 @@
+package cronjobs
+
+import (
+	"fmt"
+	"html"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TFX0019/api-go-gds/features/materials"
+	"github.com/TFX0019/api-go-gds/pkg/utils"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type lowStockMaterial struct {
+	ID               uuid.UUID
+	UserID           uint
+	Email            string
+	Name             string
+	Unit             string
+	Quantity         float64
+	Reserved         float64
+	ReorderThreshold float64
+}
+
+// SendLowStockAlerts emails each user about the materials that fell to or
+// below their reorder threshold, counting what pending orders have
+// reserved. Materials back above it are reset so the next crossing is
+// alerted again.
+func SendLowStockAlerts(db *gorm.DB) {
+	err := db.Model(&materials.Material{}).
+		Where("materials.low_stock_notified_at IS NOT NULL").
+		Where("NOT "+materials.LowStockCondition).
+		Update("low_stock_notified_at", nil).Error
+	if err != nil {
+		log.Printf("[Cronjob] Error resetting low stock alerts: %v", err)
+		return
+	}
+
+	var low []lowStockMaterial
+	err = db.Table("materials").
+		Select("materials.id, materials.user_id, users.email, materials.name, materials.unit, materials.quantity, " +
+			materials.ReservedQuantity + " AS reserved, materials.reorder_threshold").
+		Joins("JOIN users ON users.id = materials.user_id AND users.deleted_at IS NULL").
+		Where("materials.low_stock_notified_at IS NULL").
+		Where(materials.LowStockCondition).
+		Order("materials.user_id, materials.name").
+		Scan(&low).Error
+	if err != nil {
+		log.Printf("[Cronjob] Error getting low stock materials: %v", err)
+		return
+	}
+
+	byUser := map[uint][]lowStockMaterial{}
+	var userIDs []uint
+	for _, m := range low {
+		if _, ok := byUser[m.UserID]; !ok {
+			userIDs = append(userIDs, m.UserID)
+		}
+		byUser[m.UserID] = append(byUser[m.UserID], m)
+	}
+
+	for _, userID := range userIDs {
+		userMaterials := byUser[userID]
+		if err := utils.SendNotificationEmail(userMaterials[0].Email, "Materials running low", lowStockBody(userMaterials)); err != nil {
+			log.Printf("[Cronjob] Error sending low stock alert to user %d: %v", userID, err)
+			continue
+		}
+
+		ids := make([]uuid.UUID, 0, len(userMaterials))
+		for _, m := range userMaterials {
+			ids = append(ids, m.ID)
+		}
+		if err := db.Model(&materials.Material{}).Where("id IN ?", ids).Update("low_stock_notified_at", time.Now()).Error; err != nil {
+			log.Printf("[Cronjob] Error marking low stock alerts for user %d: %v", userID, err)
+		}
+		log.Printf("[Cronjob] Sent %d low stock alerts to user %d", len(userMaterials), userID)
+	}
+}
+
+func lowStockBody(low []lowStockMaterial) string {
+	var body strings.Builder
+	body.WriteString("<p>These materials are at or below their reorder threshold:</p><ul>")
+	for _, m := range low {
+		body.WriteString("<li><strong>" + html.EscapeString(m.Name) + "</strong>")
+		body.WriteString(fmt.Sprintf(" &mdash; %s %s available", formatQuantity(m.Quantity-m.Reserved), html.EscapeString(m.Unit)))
+		if m.Reserved > 0 {
+			body.WriteString(fmt.Sprintf(" (%s reserved by pending orders)", formatQuantity(m.Reserved)))
+		}
+		body.WriteString(fmt.Sprintf(", reorder at %s</li>", formatQuantity(m.ReorderThreshold)))
+	}
+	body.WriteString("</ul>")
+	return body.String()
+}
+
+func formatQuantity(q float64) string {
+	return strconv.FormatFloat(math.Round(q*100)/100, 'f', -1, 64)
+}
@@ -0,0 +1,121 @@
+package cronjobs
+
+import (
+	"fmt"
+	"html"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/TFX0019/api-go-gds/features/products"
+	"github.com/TFX0019/api-go-gds/pkg/utils"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type dueOrder struct {
+	ID         uuid.UUID
+	UserID     uint
+	Email      string
+	Name       string
+	ClientName string
+	DueDate    time.Time
+	Overdue    bool
+}
+
+// SendOrderReminders emails each user about the orders due within their
+// reminder window and the ones that just became overdue. Every order is
+// reminded once per due date and notified once when it is late.
+func SendOrderReminders(db *gorm.DB) {
+	var orders []dueOrder
+	err := db.Table("products").
+		Select(`products.id, products.user_id, users.email, products.name, COALESCE(customers.name, '') AS client_name,
+			products.due_date, products.due_date < CURRENT_DATE AS overdue`).
+		Joins("JOIN users ON users.id = products.user_id AND users.deleted_at IS NULL").
+		Joins("LEFT JOIN customers ON customers.id = products.client_id").
+		Where("products.due_date IS NOT NULL").
+		Where(products.UndeliveredCondition).
+		Where(`((products.due_date < CURRENT_DATE AND products.overdue_notified_at IS NULL) OR
+			(products.due_date >= CURRENT_DATE AND users.due_reminder_days > 0 AND
+			products.due_date <= CURRENT_DATE + users.due_reminder_days AND products.due_reminder_sent_at IS NULL))`).
+		Order("products.user_id, products.due_date").
+		Scan(&orders).Error
+	if err != nil {
+		log.Printf("[Cronjob] Error getting due orders: %v", err)
+		return
+	}
+
+	byUser := map[uint][]dueOrder{}
+	var userIDs []uint
+	for _, o := range orders {
+		if _, ok := byUser[o.UserID]; !ok {
+			userIDs = append(userIDs, o.UserID)
+		}
+		byUser[o.UserID] = append(byUser[o.UserID], o)
+	}
+
+	for _, userID := range userIDs {
+		userOrders := byUser[userID]
+		if err := utils.SendNotificationEmail(userOrders[0].Email, reminderSubject(userOrders), reminderBody(userOrders)); err != nil {
+			log.Printf("[Cronjob] Error sending order reminders to user %d: %v", userID, err)
+			continue
+		}
+
+		var upcoming, overdue []uuid.UUID
+		for _, o := range userOrders {
+			if o.Overdue {
+				overdue = append(overdue, o.ID)
+			} else {
+				upcoming = append(upcoming, o.ID)
+			}
+		}
+
+		now := time.Now()
+		if len(upcoming) > 0 {
+			if err := db.Model(&products.Product{}).Where("id IN ?", upcoming).Update("due_reminder_sent_at", now).Error; err != nil {
+				log.Printf("[Cronjob] Error marking reminders for user %d: %v", userID, err)
+			}
+		}
+		if len(overdue) > 0 {
+			if err := db.Model(&products.Product{}).Where("id IN ?", overdue).Update("overdue_notified_at", now).Error; err != nil {
+				log.Printf("[Cronjob] Error marking overdue orders for user %d: %v", userID, err)
+			}
+		}
+		log.Printf("[Cronjob] Sent %d order reminders to user %d", len(userOrders), userID)
+	}
+}
+
+func reminderSubject(orders []dueOrder) string {
+	for _, o := range orders {
+		if o.Overdue {
+			return "You have overdue orders"
+		}
+	}
+	return "Orders due soon"
+}
+
+func reminderBody(orders []dueOrder) string {
+	var upcoming, overdue strings.Builder
+	for _, o := range orders {
+		line := "<li><strong>" + html.EscapeString(o.Name) + "</strong>"
+		if o.ClientName != "" {
+			line += " for " + html.EscapeString(o.ClientName)
+		}
+		line += fmt.Sprintf(" &mdash; due %s</li>", o.DueDate.Format("2006-01-02"))
+
+		if o.Overdue {
+			overdue.WriteString(line)
+		} else {
+			upcoming.WriteString(line)
+		}
+	}
+
+	var body strings.Builder
+	if overdue.Len() > 0 {
+		body.WriteString("<p>These orders are past their due date and have not been delivered:</p><ul>" + overdue.String() + "</ul>")
+	}
+	if upcoming.Len() > 0 {
+		body.WriteString("<p>These orders are due soon:</p><ul>" + upcoming.String() + "</ul>")
+	}
+	return body.String()
+}
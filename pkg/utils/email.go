@@ -200,3 +200,33 @@ func SendCouponEmail(email, coupon string) error {
 	log.Printf("Coupon Code Email Sent to %s. ID: %s", email, sent.Id)
 	return nil
 }
+
+// SendNotificationEmail sends an email the user asked to be notified with,
+// like order reminders.
+func SendNotificationEmail(email, subject, html string) error {
+	apiKey := config.GetEnv("RESEND_API_KEY", "")
+	if apiKey == "" {
+		// Fallback to logging if no API key
+		log.Printf("[RESEND_MISSING] Notification %q for %s", subject, email)
+		return nil
+	}
+
+	client := resend.NewClient(apiKey)
+	from := config.GetEnv("RESEND_FROM", "noreply@patronesparacostura.com")
+
+	params := &resend.SendEmailRequest{
+		From:    from,
+		To:      []string{email},
+		Html:    html,
+		Subject: subject,
+	}
+
+	sent, err := client.Emails.Send(params)
+	if err != nil {
+		log.Printf("Error sending notification email with Resend: %v", err)
+		return err
+	}
+
+	log.Printf("Notification Email Sent to %s. ID: %s", email, sent.Id)
+	return nil
+}
@@ -0,0 +1,261 @@
+package utils
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	DefaultCurrency = "USD"
+	DefaultLocale   = "es-MX"
+)
+
+// Currency describes how amounts of an ISO 4217 currency are written.
+type Currency struct {
+	Code     string
+	Symbol   string
+	Decimals int
+}
+
+// Currencies are the currencies amounts can be recorded in.
+var Currencies = map[string]Currency{
+	"USD": {"USD", "US$", 2},
+	"EUR": {"EUR", "€", 2},
+	"MXN": {"MXN", "$", 2},
+	"GTQ": {"GTQ", "Q", 2},
+	"HNL": {"HNL", "L", 2},
+	"NIO": {"NIO", "C$", 2},
+	"CRC": {"CRC", "₡", 2},
+	"PAB": {"PAB", "B/.", 2},
+	"DOP": {"DOP", "RD$", 2},
+	"COP": {"COP", "$", 2},
+	"VES": {"VES", "Bs.", 2},
+	"PEN": {"PEN", "S/", 2},
+	"BOB": {"BOB", "Bs", 2},
+	"BRL": {"BRL", "R$", 2},
+	"CLP": {"CLP", "$", 0},
+	"ARS": {"ARS", "$", 2},
+	"UYU": {"UYU", "$", 2},
+	"PYG": {"PYG", "₲", 0},
+}
+
+// numberFormat holds the separators a locale writes numbers with.
+type numberFormat struct {
+	decimal   string
+	thousands string
+}
+
+var localeFormats = map[string]numberFormat{
+	"en-US": {".", ","},
+	"es-MX": {".", ","},
+	"es-GT": {".", ","},
+	"es-HN": {".", ","},
+	"es-NI": {".", ","},
+	"es-PA": {".", ","},
+	"es-DO": {".", ","},
+	"es-PE": {".", ","},
+	"es-CR": {",", "."},
+	"es-CO": {",", "."},
+	"es-VE": {",", "."},
+	"es-BO": {",", "."},
+	"es-CL": {",", "."},
+	"es-AR": {",", "."},
+	"es-UY": {",", "."},
+	"es-PY": {",", "."},
+	"es-ES": {",", "."},
+	"pt-BR": {",", "."},
+}
+
+// IsCurrency reports whether code is one of the supported currencies.
+func IsCurrency(code string) bool {
+	_, ok := Currencies[code]
+	return ok
+}
+
+// IsLocale reports whether amounts can be formatted for locale.
+func IsLocale(locale string) bool {
+	_, ok := localeFormats[locale]
+	return ok
+}
+
+// FormatMoney writes amount with the currency's symbol and decimals and the
+// locale's separators, e.g. "$1,234.50" for MXN in es-MX or "$1.234,50" for
+// ARS in es-AR. Unknown locales fall back to DefaultLocale.
+func FormatMoney(amount Money, currency, locale string) string {
+	c, ok := Currencies[currency]
+	if !ok {
+		c = Currency{Code: currency, Symbol: currency + " ", Decimals: 2}
+	}
+	format, ok := localeFormats[locale]
+	if !ok {
+		format = localeFormats[DefaultLocale]
+	}
+
+	sign := ""
+	if amount.IsNegative() {
+		sign = "-"
+		amount = amount.Neg()
+	}
+
+	text := amount.d.StringFixed(int32(c.Decimals))
+	whole, fraction, _ := strings.Cut(text, ".")
+
+	var grouped strings.Builder
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			grouped.WriteString(format.thousands)
+		}
+		grouped.WriteRune(digit)
+	}
+	if fraction != "" {
+		grouped.WriteString(format.decimal + fraction)
+	}
+
+	return sign + c.Symbol + grouped.String()
+}
+
+// MoneyPlaces is the number of decimals amounts are stored with.
+const MoneyPlaces = 2
+
+// Money is an exact monetary amount. Calculations keep every digit and
+// amounts are rounded to MoneyPlaces, half away from zero, when they are
+// stored. It is saved as a numeric column and encoded in JSON as a number
+// with two decimals, e.g. 1234.50. Amounts are not tied to a currency, so
+// currencies without minor units such as CLP are also stored and encoded
+// with two decimals; FormatMoney writes them with the currency's own.
+type Money struct {
+	d decimal.Decimal
+}
+
+// Zero is an amount of nothing.
+var Zero = Money{}
+
+// NewMoney returns the amount written by v, e.g. 0.1 is exactly 0.10.
+func NewMoney(v float64) Money {
+	return Money{decimal.NewFromFloat(v)}
+}
+
+// ParseMoney reads an amount written as a decimal number.
+func ParseMoney(s string) (Money, error) {
+	d, err := decimal.NewFromString(strings.TrimSpace(s))
+	if err != nil {
+		return Zero, fmt.Errorf("invalid amount %q", s)
+	}
+	return Money{d}, nil
+}
+
+func (m Money) Add(o Money) Money { return Money{m.d.Add(o.d)} }
+func (m Money) Sub(o Money) Money { return Money{m.d.Sub(o.d)} }
+func (m Money) Neg() Money        { return Money{m.d.Neg()} }
+
+// Mul multiplies the amount by a quantity, such as meters or hours.
+func (m Money) Mul(quantity float64) Money {
+	return Money{m.d.Mul(decimal.NewFromFloat(quantity))}
+}
+
+// Div divides the amount by a quantity. Dividing by zero gives zero.
+func (m Money) Div(quantity float64) Money {
+	if quantity == 0 {
+		return Zero
+	}
+	return Money{m.d.Div(decimal.NewFromFloat(quantity))}
+}
+
+// Percent returns percentage percent of the amount.
+func (m Money) Percent(percentage float64) Money {
+	return Money{m.d.Mul(decimal.NewFromFloat(percentage)).Div(decimal.NewFromInt(100))}
+}
+
+// Scale returns the amount multiplied by num/den, e.g. the share of a cost
+// covered by a partial payment. A zero den gives zero.
+func (m Money) Scale(num, den Money) Money {
+	if den.IsZero() {
+		return Zero
+	}
+	return Money{m.d.Mul(num.d).Div(den.d)}
+}
+
+// Round rounds the amount to MoneyPlaces.
+func (m Money) Round() Money {
+	return Money{m.d.Round(MoneyPlaces)}
+}
+
+func (m Money) Cmp(o Money) int          { return m.d.Cmp(o.d) }
+func (m Money) Equal(o Money) bool       { return m.d.Equal(o.d) }
+func (m Money) GreaterThan(o Money) bool { return m.d.GreaterThan(o.d) }
+func (m Money) LessThan(o Money) bool    { return m.d.LessThan(o.d) }
+func (m Money) IsZero() bool             { return m.d.IsZero() }
+func (m Money) IsPositive() bool         { return m.d.IsPositive() }
+func (m Money) IsNegative() bool         { return m.d.IsNegative() }
+func (m Money) Abs() Money               { return Money{m.d.Abs()} }
+func (m Money) String() string           { return m.d.StringFixed(MoneyPlaces) }
+
+// MaxMoney returns the larger of a and b.
+func MaxMoney(a, b Money) Money {
+	if a.LessThan(b) {
+		return b
+	}
+	return a
+}
+
+// MinMoney returns the smaller of a and b.
+func MinMoney(a, b Money) Money {
+	if a.GreaterThan(b) {
+		return b
+	}
+	return a
+}
+
+// SumMoney adds up amounts.
+func SumMoney(amounts ...Money) Money {
+	var sum Money
+	for _, a := range amounts {
+		sum = sum.Add(a)
+	}
+	return sum
+}
+
+// Float64 returns the closest float to the amount, for ratios and charts.
+func (m Money) Float64() float64 {
+	f, _ := m.d.Float64()
+	return f
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON accepts numbers as well as numbers written as strings.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	text := strings.Trim(string(data), `"`)
+	if text == "null" || text == "" {
+		*m = Zero
+		return nil
+	}
+	parsed, err := ParseMoney(text)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// UnmarshalText lets amounts be sent in forms and query strings.
+func (m *Money) UnmarshalText(text []byte) error {
+	return m.UnmarshalJSON(text)
+}
+
+func (m *Money) Scan(value interface{}) error {
+	if value == nil {
+		*m = Zero
+		return nil
+	}
+	return m.d.Scan(value)
+}
+
+func (m Money) Value() (driver.Value, error) {
+	return m.d.Round(MoneyPlaces).String(), nil
+}
@@ -0,0 +1,33 @@
+package utils
+
+import "math"
+
+const (
+	UnitCentimeters = "cm"
+	UnitInches      = "in"
+
+	centimetersPerInch = 2.54
+)
+
+// IsLengthUnit reports whether unit is one of the supported length units.
+func IsLengthUnit(unit string) bool {
+	return unit == UnitCentimeters || unit == UnitInches
+}
+
+// ToCentimeters converts a length expressed in unit to centimeters, the
+// canonical unit measurements are stored in.
+func ToCentimeters(value float64, unit string) float64 {
+	if unit == UnitInches {
+		return value * centimetersPerInch
+	}
+	return value
+}
+
+// FromCentimeters converts a stored length to unit. Inches are rounded to two
+// decimals to hide the conversion noise.
+func FromCentimeters(value float64, unit string) float64 {
+	if unit == UnitInches {
+		return math.Round(value/centimetersPerInch*100) / 100
+	}
+	return value
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"fmt"
+	"reflect"
 
 	"github.com/go-playground/validator/v10"
 )
@@ -26,3 +27,16 @@ func ParseValidationError(err error) string {
 	}
 	return err.Error()
 }
+
+// NewValidator returns a validator that compares Money fields as numbers, so
+// tags such as gte=0 work on amounts.
+func NewValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+		if m, ok := field.Interface().(Money); ok {
+			return m.Float64()
+		}
+		return nil
+	}, Money{})
+	return v
+}
@@ -0,0 +1,125 @@
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// VCard holds the contact properties the API imports and exports.
+type VCard struct {
+	FullName string
+	Phones   []string
+	Emails   []string
+	Note     string
+}
+
+// ParseVCards reads every card in a vCard 2.1/3.0/4.0 file.
+func ParseVCards(r io.Reader) ([]VCard, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		// Folded lines continue the previous one
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	var cards []VCard
+	var current *VCard
+	var structuredName string
+	for _, line := range lines {
+		sep := strings.Index(line, ":")
+		if sep == -1 {
+			continue
+		}
+		params := strings.Split(line[:sep], ";")
+		// Drop group prefixes such as "item1.TEL"
+		key := strings.ToUpper(params[0])
+		if dot := strings.LastIndex(key, "."); dot != -1 {
+			key = key[dot+1:]
+		}
+		value := line[sep+1:]
+
+		switch {
+		case key == "BEGIN" && strings.EqualFold(value, "VCARD"):
+			current = &VCard{}
+			structuredName = ""
+		case key == "END" && strings.EqualFold(value, "VCARD"):
+			if current != nil {
+				if current.FullName == "" {
+					current.FullName = structuredName
+				}
+				cards = append(cards, *current)
+			}
+			current = nil
+		case current == nil:
+			continue
+		case key == "FN":
+			current.FullName = unescapeVCardValue(value)
+		case key == "N":
+			// N is family;given;additional;prefix;suffix
+			parts := strings.Split(value, ";")
+			var names []string
+			for _, i := range []int{3, 1, 2, 0, 4} {
+				if i < len(parts) && parts[i] != "" {
+					names = append(names, unescapeVCardValue(parts[i]))
+				}
+			}
+			structuredName = strings.Join(names, " ")
+		case key == "TEL":
+			if v := strings.TrimPrefix(unescapeVCardValue(value), "tel:"); v != "" {
+				current.Phones = append(current.Phones, v)
+			}
+		case key == "EMAIL":
+			if v := unescapeVCardValue(value); v != "" {
+				current.Emails = append(current.Emails, v)
+			}
+		case key == "NOTE":
+			current.Note = unescapeVCardValue(value)
+		}
+	}
+
+	return cards, nil
+}
+
+// WriteVCard writes a card in vCard 3.0 format.
+func WriteVCard(w io.Writer, card VCard) error {
+	var b strings.Builder
+	b.WriteString("BEGIN:VCARD\r\nVERSION:3.0\r\n")
+	fmt.Fprintf(&b, "FN:%s\r\n", escapeVCardValue(card.FullName))
+	fmt.Fprintf(&b, "N:;%s;;;\r\n", escapeVCardValue(card.FullName))
+	for _, phone := range card.Phones {
+		fmt.Fprintf(&b, "TEL;TYPE=CELL:%s\r\n", escapeVCardValue(phone))
+	}
+	for _, email := range card.Emails {
+		fmt.Fprintf(&b, "EMAIL;TYPE=INTERNET:%s\r\n", escapeVCardValue(email))
+	}
+	if card.Note != "" {
+		fmt.Fprintf(&b, "NOTE:%s\r\n", escapeVCardValue(card.Note))
+	}
+	b.WriteString("END:VCARD\r\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+var vcardEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, ",", `\,`, ";", `\;`)
+
+func escapeVCardValue(s string) string {
+	return vcardEscaper.Replace(strings.ReplaceAll(s, "\r", ""))
+}
+
+var vcardUnescaper = strings.NewReplacer(`\\`, `\`, `\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";")
+
+func unescapeVCardValue(s string) string {
+	return strings.TrimSpace(vcardUnescaper.Replace(s))
+}